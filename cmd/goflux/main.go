@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/internal/cli"
 	"github.com/slatkin/goflux/internal/ui"
 	"github.com/slatkin/goflux/pkg/config"
 )
 
 func main() {
 	initFlag := flag.Bool("init", false, "Initialize default configuration file")
+	profileFlag := flag.String("profile", "", "Server profile from [profiles.<name>] to connect to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: goflux [flags] [command]\n\nFlags:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\n%s", cli.Usage())
+	}
 	flag.Parse()
 
 	if *initFlag {
@@ -24,7 +32,25 @@ func main() {
 		os.Exit(0)
 	}
 
-	cfg, err := config.Load()
+	cfg, err := config.Load(*profileFlag)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, w := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+
+	if flag.NArg() > 0 {
+		if err := cli.Run(context.Background(), cfg, flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err = cfg.ResolveCredentials()
 	if err != nil {
 		fmt.Printf("Error loading config: %v\n", err)
 		os.Exit(1)
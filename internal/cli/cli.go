@@ -0,0 +1,103 @@
+// Package cli implements the goflux subcommands that run without the TUI.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/slatkin/goflux/internal/client"
+	"github.com/slatkin/goflux/pkg/config"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+type command struct {
+	Name  string
+	Usage string
+	Run   func(ctx context.Context, env *env, args []string) error
+}
+
+// env is what a command runs against.
+type env struct {
+	Config config.Config
+	Client *miniflux.Client
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+var commands = []command{
+	{"list", listUsage, connected(runList)},
+	{"show", showUsage, connected(runShow)},
+	{"read", readUsage, connected(runRead)},
+	{"unread", unreadUsage, connected(runUnread)},
+	{"star", starUsage, connected(runStar)},
+	{"unstar", unstarUsage, connected(runUnstar)},
+	{"save", saveUsage, connected(runSave)},
+	{"refresh", refreshUsage, connected(runRefresh)},
+	{"feeds", feedsUsage, connected(runFeeds)},
+	{"categories", categoriesUsage, connected(runCategories)},
+	{"counts", countsUsage, connected(runCounts)},
+	{"count", countUsage, runCount},
+	{"opml", opmlUsage, connected(runOPML)},
+}
+
+// connected resolves the credentials before running a command that
+// needs the server. count connects itself, only when its cache is stale.
+func connected(run func(ctx context.Context, e *env, args []string) error) func(ctx context.Context, e *env, args []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if err := e.connect(); err != nil {
+			return err
+		}
+		return run(ctx, e, args)
+	}
+}
+
+// connect resolves the credentials, which may run api_key_command, and
+// creates the client.
+func (e *env) connect() error {
+	cfg, err := e.Config.ResolveCredentials()
+	if err != nil {
+		return err
+	}
+	e.Config, e.Client = cfg, client.New(cfg)
+	return nil
+}
+
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// Run executes the subcommand named by args[0].
+func Run(ctx context.Context, cfg config.Config, args []string) error {
+	cmd, ok := lookup(args[0])
+	if !ok {
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], Usage())
+	}
+	e := &env{Config: cfg, Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	return cmd.Run(ctx, e, args[1:])
+}
+
+// Usage lists the subcommands.
+func Usage() string {
+	var s strings.Builder
+	s.WriteString("Commands:\n")
+	for _, cmd := range commands {
+		for _, line := range strings.Split(cmd.Usage, "\n") {
+			s.WriteString("  goflux " + line + "\n")
+		}
+	}
+	return s.String()
+}
+
+// usageError reports a command invoked with the wrong arguments.
+func usageError(usage string) error {
+	return fmt.Errorf("usage: goflux %s", strings.ReplaceAll(usage, "\n", "\n       goflux "))
+}
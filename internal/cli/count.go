@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/unread"
+)
+
+const countUsage = "count [flags]"
+
+// runCount prints unread counts for status bars. With --cache the counts
+// are fetched at most once per duration.
+func runCount(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("count", flag.ContinueOnError)
+	style := fs.String("style", "plain", "output style: "+strings.Join(unread.Styles, ", "))
+	maxAge := fs.Duration("cache", 0, "reuse counts fetched within this long, e.g. 30s")
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, countUsage); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(countUsage)
+	}
+	if !slices.Contains(unread.Styles, *style) {
+		return fmt.Errorf("unknown --style %q (want %s)", *style, strings.Join(unread.Styles, ", "))
+	}
+
+	counts, err := fetchCounts(ctx, e, *maxAge)
+	if err != nil {
+		return err
+	}
+
+	if done, err := printItem(e.Stdout, out, counts); done {
+		return err
+	}
+	text, err := counts.Format(*style)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.Stdout, text)
+	return err
+}
+
+// fetchCounts reads a fresh cache before connecting, so polling does not
+// run api_key_command every time. Failing to write the cache is not an
+// error.
+func fetchCounts(ctx context.Context, e *env, maxAge time.Duration) (unread.Counts, error) {
+	var path string
+	if maxAge > 0 {
+		var err error
+		if path, err = e.Config.CountCachePath(); err != nil {
+			return unread.Counts{}, err
+		}
+		if counts, ok := unread.Cached(path, maxAge); ok {
+			return counts, nil
+		}
+	}
+
+	if err := e.connect(); err != nil {
+		return unread.Counts{}, err
+	}
+	counts, err := unread.Fetch(ctx, e.Client)
+	if err != nil {
+		return unread.Counts{}, err
+	}
+	if path != "" {
+		_ = unread.SaveCache(path, counts)
+	}
+	return counts, nil
+}
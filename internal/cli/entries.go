@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaytaylor/html2text"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+const (
+	listUsage   = "list [flags]"
+	showUsage   = "show [flags] <entry-id>"
+	readUsage   = "read <entry-id>..."
+	unreadUsage = "unread <entry-id>..."
+	starUsage   = "star <entry-id>..."
+	unstarUsage = "unstar <entry-id>..."
+	saveUsage   = "save <entry-id>..."
+)
+
+// runList prints entries, newest first. Columns: id, status, starred,
+// published, feed, title, url.
+func runList(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	status := fs.String("status", "unread", "unread, read or all")
+	starred := fs.Bool("starred", false, "only starred entries")
+	feedID := fs.Int("feed", 0, "only entries of this feed")
+	categoryID := fs.Int("category", 0, "only entries of this category")
+	search := fs.String("search", "", "full-text search")
+	limit := fs.Int("limit", 100, "maximum number of entries")
+	offset := fs.Int("offset", 0, "skip this many entries")
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, listUsage); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(listUsage)
+	}
+
+	q := miniflux.EntryQuery{
+		Search:     *search,
+		FeedID:     *feedID,
+		CategoryID: *categoryID,
+		Order:      miniflux.OrderPublishedAt,
+		Direction:  miniflux.DirectionDesc,
+		Limit:      *limit,
+		Offset:     *offset,
+	}
+	switch *status {
+	case "unread", "read":
+		q.Status = []miniflux.ReadStatus{miniflux.ReadStatus(*status)}
+	case "all":
+	default:
+		return fmt.Errorf("unknown --status %q (want unread, read or all)", *status)
+	}
+	if *starred {
+		q.Starred = starred
+	}
+
+	entries, _, err := e.Client.GetEntries(ctx, q)
+	if err != nil {
+		return err
+	}
+	return printList(e.Stdout, out, entries, func(entry miniflux.FeedEntry) []string {
+		return []string{
+			strconv.Itoa(entry.ID),
+			string(entry.Status),
+			strconv.FormatBool(entry.Starred),
+			entry.PublishedAt.Format(time.RFC3339),
+			entry.Feed.Title,
+			entry.Title,
+			entry.URL,
+		}
+	})
+}
+
+// runShow prints one entry with its content as plain text.
+func runShow(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, showUsage); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args(), showUsage)
+	if err != nil {
+		return err
+	}
+	if len(ids) != 1 {
+		return usageError(showUsage)
+	}
+
+	entry, err := e.Client.GetEntry(ctx, ids[0])
+	if err != nil {
+		return err
+	}
+	if done, err := printItem(e.Stdout, out, entry); done {
+		return err
+	}
+
+	text, err := html2text.FromString(entry.Content, html2text.Options{PrettyTables: true})
+	if err != nil {
+		return err
+	}
+	flags := []string{string(entry.Status)}
+	if entry.Starred {
+		flags = append(flags, "starred")
+	}
+	_, err = fmt.Fprintf(e.Stdout, "%s\n%s | %s | %s\n%s\n\n%s\n",
+		entry.Title,
+		entry.Feed.Title,
+		entry.PublishedAt.Local().Format("2006-01-02 15:04"),
+		strings.Join(flags, ", "),
+		entry.URL,
+		text,
+	)
+	return err
+}
+
+func runRead(ctx context.Context, e *env, args []string) error {
+	return setStatus(ctx, e, args, miniflux.ReadStatusRead, readUsage)
+}
+
+func runUnread(ctx context.Context, e *env, args []string) error {
+	return setStatus(ctx, e, args, miniflux.ReadStatusUnread, unreadUsage)
+}
+
+func setStatus(ctx context.Context, e *env, args []string, status miniflux.ReadStatus, usage string) error {
+	ids, err := parseIDs(args, usage)
+	if err != nil {
+		return err
+	}
+	return e.Client.ChangeEntryReadStatus(ctx, ids, status)
+}
+
+func runStar(ctx context.Context, e *env, args []string) error {
+	return setStarred(ctx, e, args, true, starUsage)
+}
+
+func runUnstar(ctx context.Context, e *env, args []string) error {
+	return setStarred(ctx, e, args, false, unstarUsage)
+}
+
+// setStarred checks each entry first because the API can only toggle the
+// star, and running the command twice should not undo it.
+func setStarred(ctx context.Context, e *env, args []string, starred bool, usage string) error {
+	ids, err := parseIDs(args, usage)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		entry, err := e.Client.GetEntry(ctx, id)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", id, err)
+		}
+		if entry.Starred == starred {
+			continue
+		}
+		if err := e.Client.ToggleStarred(ctx, id); err != nil {
+			return fmt.Errorf("entry %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func runSave(ctx context.Context, e *env, args []string) error {
+	ids, err := parseIDs(args, saveUsage)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := e.Client.SaveEntry(ctx, id); err != nil {
+			return fmt.Errorf("entry %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// parseIDs reads entry or feed IDs; at least one is required.
+func parseIDs(args []string, usage string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, usageError(usage)
+	}
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
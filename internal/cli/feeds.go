@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+const (
+	refreshUsage    = "refresh [feed-id...]"
+	feedsUsage      = "feeds [flags]"
+	categoriesUsage = "categories [flags]"
+	countsUsage     = "counts [flags]"
+)
+
+// feedRow is a feed with its entry counts, as printed by the feeds command.
+type feedRow struct {
+	miniflux.Feed
+	Unread int `json:"unread"`
+	Read   int `json:"read"`
+}
+
+type categoryRow struct {
+	miniflux.Category
+	Feeds  int `json:"feeds"`
+	Unread int `json:"unread"`
+}
+
+type countRow struct {
+	FeedID int    `json:"feed_id"`
+	Feed   string `json:"feed"`
+	Unread int    `json:"unread"`
+	Read   int    `json:"read"`
+}
+
+// runRefresh asks the server to fetch the given feeds, or all of them.
+func runRefresh(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return e.Client.RefreshAllFeeds(ctx)
+	}
+	ids, err := parseIDs(args, refreshUsage)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := e.Client.RefreshFeed(ctx, id); err != nil {
+			return fmt.Errorf("feed %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// runFeeds prints the subscriptions by category and title. Columns: id,
+// category, unread, title, feed url.
+func runFeeds(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("feeds", flag.ContinueOnError)
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, feedsUsage); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(feedsUsage)
+	}
+
+	rows, err := feedRows(ctx, e.Client)
+	if err != nil {
+		return err
+	}
+	return printList(e.Stdout, out, rows, func(r feedRow) []string {
+		return []string{strconv.Itoa(r.ID), r.Category.Title, strconv.Itoa(r.Unread), r.Title, r.FeedURL}
+	})
+}
+
+func feedRows(ctx context.Context, c *miniflux.Client) ([]feedRow, error) {
+	feeds, err := c.GetFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counters, err := c.GetFeedCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]feedRow, len(feeds))
+	for i, feed := range feeds {
+		rows[i] = feedRow{Feed: feed, Unread: counters.Unreads[feed.ID], Read: counters.Reads[feed.ID]}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := strings.ToLower(rows[i].Category.Title), strings.ToLower(rows[j].Category.Title)
+		if a != b {
+			return a < b
+		}
+		return strings.ToLower(rows[i].Title) < strings.ToLower(rows[j].Title)
+	})
+	return rows, nil
+}
+
+// runCategories prints the categories by title. Columns: id, title,
+// feeds, unread.
+func runCategories(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("categories", flag.ContinueOnError)
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, categoriesUsage); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(categoriesUsage)
+	}
+
+	categories, err := e.Client.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+	feeds, err := feedRows(ctx, e.Client)
+	if err != nil {
+		return err
+	}
+	rows := make([]categoryRow, len(categories))
+	index := make(map[int]int, len(categories))
+	for i, category := range categories {
+		rows[i] = categoryRow{Category: category}
+		index[category.ID] = i
+	}
+	for _, feed := range feeds {
+		if i, ok := index[feed.Category.ID]; ok {
+			rows[i].Feeds++
+			rows[i].Unread += feed.Unread
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return strings.ToLower(rows[i].Title) < strings.ToLower(rows[j].Title)
+	})
+	return printList(e.Stdout, out, rows, func(r categoryRow) []string {
+		return []string{strconv.Itoa(r.ID), r.Title, strconv.Itoa(r.Feeds), strconv.Itoa(r.Unread)}
+	})
+}
+
+// runCounts prints the read and unread counts of every feed with entries.
+// Columns: feed id, unread, read, feed.
+func runCounts(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("counts", flag.ContinueOnError)
+	out := addOutputFlags(fs)
+	if err := parseFlags(fs, args, countsUsage); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError(countsUsage)
+	}
+
+	feeds, err := feedRows(ctx, e.Client)
+	if err != nil {
+		return err
+	}
+	var rows []countRow
+	for _, feed := range feeds {
+		if feed.Unread > 0 || feed.Read > 0 {
+			rows = append(rows, countRow{FeedID: feed.ID, Feed: feed.Title, Unread: feed.Unread, Read: feed.Read})
+		}
+	}
+	return printList(e.Stdout, out, rows, func(r countRow) []string {
+		return []string{strconv.Itoa(r.FeedID), strconv.Itoa(r.Unread), strconv.Itoa(r.Read), r.Feed}
+	})
+}
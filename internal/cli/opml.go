@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/slatkin/goflux/pkg/config"
+	"github.com/slatkin/goflux/pkg/miniflux"
+	"github.com/slatkin/goflux/pkg/opml"
+	"github.com/slatkin/goflux/pkg/store"
+)
+
+const opmlUsage = "opml export [file]\nopml import [--dry-run] <file>"
+
+func runOPML(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return usageError(opmlUsage)
+	}
+	switch args[0] {
+	case "export":
+		return opmlExport(ctx, e, args[1:])
+	case "import":
+		return opmlImport(ctx, e, args[1:])
+	}
+	return usageError(opmlUsage)
+}
+
+// opmlExport writes the server's subscriptions to a file, or to stdout
+// when no file is given.
+func opmlExport(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return usageError(opmlUsage)
+	}
+	data, err := e.Client.Export(ctx)
+	if err != nil {
+		var ferr error
+		if data, ferr = exportFeeds(ctx, e, err); ferr != nil {
+			return err
+		}
+	}
+	if len(args) == 0 || args[0] == "-" {
+		_, err := e.Stdout.Write(data)
+		return err
+	}
+
+	// Feed URLs can carry access tokens
+	if err := os.WriteFile(args[0], data, 0600); err != nil {
+		return err
+	}
+	subs, err := opml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.Stdout, "Exported %s to %s\n", feeds(len(subs)), args[0])
+	return nil
+}
+
+// opmlImport shows how the file differs from the server's subscriptions,
+// then imports it unless --dry-run is given.
+func opmlImport(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("opml import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only show what would change")
+	if err := parseFlags(fs, args, opmlUsage); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError(opmlUsage)
+	}
+
+	path := fs.Arg(0)
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(e.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	subs, err := opml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	serverFeeds, err := e.Client.GetFeeds(ctx)
+	if err != nil {
+		return err
+	}
+	diff := opml.Compare(subs, subscriptions(serverFeeds))
+
+	if *dryRun {
+		printSubscriptions(e.Stdout, feeds(len(diff.Added))+" would be added", "+", diff.Added)
+	} else {
+		printSubscriptions(e.Stdout, "Adding "+feeds(len(diff.Added)), "+", diff.Added)
+	}
+	if len(diff.Missing) > 0 {
+		printSubscriptions(e.Stdout, feeds(len(diff.Missing))+" on the server but not in "+path, "-", diff.Missing)
+	}
+	if *dryRun || len(diff.Added) == 0 {
+		return nil
+	}
+
+	if err := e.Client.Import(ctx, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.Stdout, "Imported %s\n", feeds(len(diff.Added)))
+	return nil
+}
+
+// exportFeeds builds the OPML document from the feed list when the
+// server's export failed, or from the offline cache when the server
+// cannot be reached at all.
+func exportFeeds(ctx context.Context, e *env, exportErr error) ([]byte, error) {
+	source := "the feed list"
+	feeds, err := e.Client.GetFeeds(ctx)
+	if err != nil && e.Config.Cache.Enabled {
+		source = "the cached feeds"
+		feeds, err = cachedFeeds(e.Config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := opml.Write(&buf, "goflux subscriptions", subscriptions(feeds)); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(e.Stderr, "Warning: %v - exporting %s instead\n", exportErr, source)
+	return buf.Bytes(), nil
+}
+
+func cachedFeeds(cfg config.Config) ([]miniflux.Feed, error) {
+	path, err := cfg.CachePath()
+	if err != nil {
+		return nil, err
+	}
+	// Opening would create an empty cache
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	s, err := store.Open(path, cfg.ServerUrl)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	feeds, err := s.Feeds()
+	if err == nil && len(feeds) == 0 {
+		err = errors.New("no feeds cached")
+	}
+	return feeds, err
+}
+
+func subscriptions(feeds []miniflux.Feed) []opml.Subscription {
+	subs := make([]opml.Subscription, len(feeds))
+	for i, feed := range feeds {
+		subs[i] = opml.Subscription{
+			Title:    feed.Title,
+			FeedURL:  feed.FeedURL,
+			SiteURL:  feed.SiteURL,
+			Category: feed.Category.Title,
+		}
+	}
+	return subs
+}
+
+func printSubscriptions(w io.Writer, heading, mark string, subs []opml.Subscription) {
+	if len(subs) == 0 {
+		fmt.Fprintln(w, heading)
+		return
+	}
+	fmt.Fprintln(w, heading+":")
+	for _, sub := range subs {
+		category := ""
+		if sub.Category != "" {
+			category = " [" + sub.Category + "]"
+		}
+		fmt.Fprintf(w, "  %s %s <%s>%s\n", mark, sub.Title, sub.FeedURL, category)
+	}
+}
+
+func feeds(n int) string {
+	if n == 1 {
+		return "1 feed"
+	}
+	return fmt.Sprintf("%d feeds", n)
+}
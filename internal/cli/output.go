@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// output selects how a command prints its results: JSON, a Go template
+// run once per item, or tab-separated columns.
+type output struct {
+	JSON   bool
+	Format string
+}
+
+func addOutputFlags(fs *flag.FlagSet) *output {
+	o := &output{}
+	fs.BoolVar(&o.JSON, "json", false, "print JSON")
+	fs.StringVar(&o.Format, "format", "", "print each item with a Go template, e.g. '{{.ID}} {{.Title}}'")
+	return o
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join": strings.Join,
+}
+
+// printList prints items, using row for the tab-separated columns.
+func printList[T any](w io.Writer, o *output, items []T, row func(T) []string) error {
+	if o.JSON {
+		if items == nil {
+			items = []T{}
+		}
+		return printJSON(w, items)
+	}
+	if o.Format != "" {
+		tmpl, err := o.template()
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := execute(w, tmpl, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, item := range items {
+		if _, err := io.WriteString(w, tsvLine(row(item))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printItem prints a single result with --json or --format. It reports
+// false when neither is set and the command should print its own text.
+func printItem(w io.Writer, o *output, v any) (bool, error) {
+	switch {
+	case o.JSON:
+		return true, printJSON(w, v)
+	case o.Format != "":
+		tmpl, err := o.template()
+		if err != nil {
+			return true, err
+		}
+		return true, execute(w, tmpl, v)
+	}
+	return false, nil
+}
+
+func (o *output) template() (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(o.Format)
+	if err != nil {
+		return nil, fmt.Errorf("--format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// execute runs tmpl for one item, ending the output with a newline.
+func execute(w io.Writer, tmpl *template.Template, v any) error {
+	if err := tmpl.Execute(w, v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// tsvLine joins fields with tabs, replacing tabs and newlines inside them
+// so every item stays on one line.
+func tsvLine(fields []string) string {
+	for i, f := range fields {
+		fields[i] = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(f)
+	}
+	return strings.Join(fields, "\t") + "\n"
+}
+
+// parseFlags parses a command's flags. Errors, including -h, list the
+// command's usage and flags.
+func parseFlags(fs *flag.FlagSet, args []string, usage string) error {
+	fs.SetOutput(io.Discard)
+	err := fs.Parse(args)
+	if err == nil {
+		return nil
+	}
+	var defaults strings.Builder
+	fs.SetOutput(&defaults)
+	fs.PrintDefaults()
+	msg := usageError(usage).Error()
+	if defaults.Len() > 0 {
+		msg += "\n\nFlags:\n" + strings.TrimRight(defaults.String(), "\n")
+	}
+	if !errors.Is(err, flag.ErrHelp) {
+		msg = err.Error() + "\n" + msg
+	}
+	return errors.New(msg)
+}
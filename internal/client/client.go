@@ -0,0 +1,43 @@
+// Package client connects to the Miniflux server described by a config.
+package client
+
+import (
+	"github.com/slatkin/goflux/pkg/config"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// New builds an API client for the server selected in cfg.
+func New(cfg config.Config) *miniflux.Client {
+	var auth miniflux.Authenticator = miniflux.TokenAuth(cfg.ApiKey)
+	if cfg.Auth == "basic" {
+		auth = miniflux.BasicAuth{Username: cfg.Username, Password: cfg.Password}
+	}
+	if len(cfg.Headers) > 0 {
+		auth = miniflux.MultiAuth{miniflux.HeaderAuth(cfg.Headers), auth}
+	}
+
+	opts := []miniflux.Option{
+		miniflux.WithAuth(auth),
+		miniflux.WithTLS(miniflux.TLSOptions{
+			InsecureSkipVerify: cfg.AllowInvalidCerts,
+			CAFile:             cfg.CAFile,
+			CertFile:           cfg.ClientCert,
+			KeyFile:            cfg.ClientKey,
+			MinVersion:         cfg.TLSMinVersion,
+			Pins:               cfg.PinnedSHA256,
+		}),
+		miniflux.WithTimeout(cfg.RequestTimeout),
+		miniflux.WithRetryPolicy(miniflux.RetryPolicy{
+			MaxRetries: cfg.Retry.MaxRetries,
+			BaseDelay:  cfg.Retry.BaseDelay,
+			MaxDelay:   cfg.Retry.MaxDelay,
+		}),
+	}
+	if cfg.ProxyURL != "" {
+		opts = append(opts, miniflux.WithProxy(cfg.ProxyURL))
+	}
+	if cfg.UnixSocket != "" {
+		opts = append(opts, miniflux.WithUnixSocket(cfg.UnixSocket))
+	}
+	return miniflux.NewClient(cfg.ServerUrl, cfg.ApiKey, cfg.AllowInvalidCerts, opts...)
+}
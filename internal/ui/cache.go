@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/config"
+	"github.com/slatkin/goflux/pkg/miniflux"
+	"github.com/slatkin/goflux/pkg/store"
+)
+
+// openStore opens the offline store for the active profile, or returns
+// nil when the cache is turned off.
+func openStore(cfg config.Config) (*store.Store, error) {
+	if !cfg.Cache.Enabled {
+		return nil, nil
+	}
+	path, err := cfg.CachePath()
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(path, cfg.ServerUrl)
+}
+
+// closeStore stops the sync and outbox replay, which may be using the
+// store, then closes it.
+func (m *Model) closeStore() {
+	if m.cancelFlush != nil {
+		m.cancelFlush()
+		m.cancelFlush = nil
+	}
+	if m.cancelSync != nil {
+		m.cancelSync()
+		m.cancelSync = nil
+	}
+	if m.Store != nil {
+		m.Store.Close()
+		m.Store = nil
+	}
+}
+
+// load fills the list after startup or a profile switch. With a cache the
+// stored entries are shown right away while a sync runs; a cache that has
+// never synced waits for the sync instead of showing an empty list.
+func (m *Model) load() tea.Cmd {
+	var flush tea.Cmd
+	if m.Queued > 0 {
+		flush = m.flushOutbox()
+	}
+	if m.Store == nil {
+		return tea.Batch(m.fetchEntries(0), m.fetchUnreadCount, flush)
+	}
+	cmds := []tea.Cmd{m.sync(), flush}
+	if cursor, err := m.Store.Cursor(); err == nil && !cursor.IsZero() {
+		cmds = append(cmds, m.fetchEntries(0), m.fetchUnreadCount)
+	}
+	return tea.Batch(cmds...)
+}
+
+// refresh reloads the current list from the server, through the cache
+// when there is one.
+func (m *Model) refresh() tea.Cmd {
+	if m.Store != nil {
+		if m.Syncing {
+			return nil
+		}
+		return m.sync()
+	}
+	m.State = StateLoading
+	return tea.Batch(m.fetchEntries(0), m.fetchUnreadCount)
+}
+
+func (m *Model) sync() tea.Cmd {
+	m.Syncing = true
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelSync = cancel
+	s, client, gen := m.Store, m.Client, m.profileGen
+	retain := time.Duration(m.Config.Cache.RetainDays) * 24 * time.Hour
+	return func() tea.Msg {
+		defer cancel()
+		result, err := s.Sync(ctx, client, retain)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return SyncedMsg{Result: result, Err: err, gen: gen}
+	}
+}
+
+func (m *Model) synced(msg SyncedMsg) tea.Cmd {
+	if msg.gen != m.profileGen {
+		return nil
+	}
+	m.Syncing = false
+	if msg.Err != nil {
+		if !m.ready {
+			m.Err = msg.Err
+			m.State = StateError
+			return nil
+		}
+		if m.State == StateLoading {
+			m.State = StateList
+		}
+		return m.setStatus("Showing cached entries: "+m.errorText(msg.Err), true)
+	}
+
+	// Other views are reloaded from the store when next shown
+	m.SavedLists = [viewCount]entryList{}
+	cmds := []tea.Cmd{m.reloadEntries(), m.fetchUnreadCount}
+	if m.State == StateFeeds {
+		cmds = append(cmds, m.fetchFeedTree)
+	}
+	if m.Queued > 0 {
+		// The server is reachable again
+		cmds = append(cmds, m.flushOutbox())
+	}
+	if msg.Result.Entries > 0 && !msg.Result.Full {
+		cmds = append(cmds, m.setStatus(fmt.Sprintf("Synced %d new or changed entries", msg.Result.Entries), false))
+	}
+	return tea.Batch(cmds...)
+}
+
+// storeStatus mirrors a status change the server accepted into the cache.
+// Failures are ignored; the next sync corrects the cache anyway.
+func (m Model) storeStatus(ids []int, status miniflux.ReadStatus) {
+	if m.Store != nil {
+		_ = m.Store.SetStatus(ids, status)
+	}
+}
+
+// isOffline reports whether err means the server could not be reached,
+// either with no response at all or with a gateway or server error.
+func isOffline(err error) bool {
+	var apiErr *miniflux.APIError
+	return !errors.As(err, &apiErr) || miniflux.IsServerError(err)
+}
+
+// cursorAfterReload finds the entry under the cursor in the reloaded
+// list, or keeps the position when it is gone.
+func cursorAfterReload(old, reloaded []miniflux.FeedEntry, cursor int) int {
+	if cursor < len(old) {
+		for i, entry := range reloaded {
+			if entry.ID == old[cursor].ID {
+				return i
+			}
+		}
+	}
+	return max(0, min(cursor, len(reloaded)-1))
+}
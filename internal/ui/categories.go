@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+func (m *Model) openNewCategory() tea.Cmd {
+	return m.openForm(&form{
+		Title:  "New category",
+		Fields: []formField{textField("Title", "")},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			title := f.Fields[0].Value()
+			if title == "" {
+				f.Busy = false
+				return nil
+			}
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				if _, err := client.CreateCategory(ctx, title); err != nil {
+					return errorMsg(err)
+				}
+				return FeedsChangedMsg{Text: "Created category " + title}
+			}
+		},
+	})
+}
+
+func (m *Model) openRenameCategory(node feedNode) tea.Cmd {
+	return m.openForm(&form{
+		Title:  "Rename " + node.Title,
+		Fields: []formField{textField("Title", node.Title)},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			title := f.Fields[0].Value()
+			if title == "" || title == node.Title {
+				m.closeForm()
+				return nil
+			}
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				category, err := client.UpdateCategory(ctx, node.ID, title)
+				if err != nil {
+					return errorMsg(err)
+				}
+				return FeedsChangedMsg{Text: "Renamed " + node.Title + " to " + title, Category: category}
+			}
+		},
+	})
+}
+
+// promptDeleteCategory confirms before deleting the category under the
+// cursor. The server would delete its feeds along with it, so only empty
+// categories can be deleted.
+func (m *Model) promptDeleteCategory(node feedNode) tea.Cmd {
+	if len(m.categoryFeeds(node.ID)) > 0 {
+		return m.setStatus(node.Title+" still has feeds - move or unsubscribe them first", true)
+	}
+	m.confirm(fmt.Sprintf("Delete category %q?", node.Title), func(m *Model) tea.Cmd {
+		client, ctx := m.Client, m.ctx
+		return func() tea.Msg {
+			if err := client.DeleteCategory(ctx, node.ID); err != nil {
+				return errorMsg(err)
+			}
+			return FeedsChangedMsg{Text: "Deleted category " + node.Title, Category: miniflux.Category{ID: node.ID}, Deleted: true}
+		}
+	})
+	return nil
+}
+
+// categoryFeeds lists the feed nodes below a category in the feed tree.
+func (m Model) categoryFeeds(categoryID int) []feedNode {
+	var feeds []feedNode
+	in := false
+	for _, node := range m.FeedTree {
+		switch node.Kind {
+		case feedNodeCategory:
+			in = node.ID == categoryID
+		case feedNodeFeed:
+			if in {
+				feeds = append(feeds, node)
+			}
+		}
+	}
+	return feeds
+}
+
+// toggleFeedSelection marks the feed under the cursor for a bulk move and
+// steps to the next node.
+func (m *Model) toggleFeedSelection() {
+	if len(m.FeedTree) == 0 || m.FeedTree[m.FeedCursor].Kind != feedNodeFeed {
+		return
+	}
+	id := m.FeedTree[m.FeedCursor].ID
+	if m.FeedSelection[id] {
+		delete(m.FeedSelection, id)
+	} else {
+		if m.FeedSelection == nil {
+			m.FeedSelection = make(map[int]bool)
+		}
+		m.FeedSelection[id] = true
+	}
+	if m.FeedCursor < len(m.FeedTree)-1 {
+		m.FeedCursor++
+	}
+}
+
+// selectedFeeds is the selected feeds in tree order, or the feed under
+// the cursor when none are selected.
+func (m Model) selectedFeeds() []feedNode {
+	var feeds []feedNode
+	for _, node := range m.FeedTree {
+		if node.Kind == feedNodeFeed && m.FeedSelection[node.ID] {
+			feeds = append(feeds, node)
+		}
+	}
+	if len(feeds) == 0 && len(m.FeedTree) > 0 && m.FeedTree[m.FeedCursor].Kind == feedNodeFeed {
+		feeds = append(feeds, m.FeedTree[m.FeedCursor])
+	}
+	return feeds
+}
+
+// openMoveFeeds asks for the category to move the selected feeds to.
+func (m *Model) openMoveFeeds() tea.Cmd {
+	feeds := m.selectedFeeds()
+	titles, ids := m.categoryChoices()
+	if len(feeds) == 0 || len(ids) == 0 {
+		return nil
+	}
+	title := "Move " + feeds[0].Title
+	if len(feeds) > 1 {
+		title = fmt.Sprintf("Move %d feeds", len(feeds))
+	}
+	return m.openForm(&form{
+		Title:  title,
+		Fields: []formField{choiceField("Category", titles, indexOf(ids, m.cursorCategory()))},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			categoryID, category := ids[f.Fields[0].Choice], titles[f.Fields[0].Choice]
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				req := miniflux.FeedModificationRequest{CategoryID: &categoryID}
+				for i, feed := range feeds {
+					if _, err := client.UpdateFeed(ctx, feed.ID, req); err != nil {
+						return errorMsg(fmt.Errorf("moved %d of %d feeds: %w", i, len(feeds), err))
+					}
+				}
+				if len(feeds) == 1 {
+					return FeedsChangedMsg{Text: fmt.Sprintf("Moved %s to %s", feeds[0].Title, category)}
+				}
+				return FeedsChangedMsg{Text: fmt.Sprintf("Moved %d feeds to %s", len(feeds), category)}
+			}
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmPrompt is a yes/no question shown in place of the status bar.
+// OnYes runs only if the user answers "y".
+type confirmPrompt struct {
+	Text  string
+	OnYes func(m *Model) tea.Cmd
+}
+
+func (m *Model) confirm(text string, onYes func(m *Model) tea.Cmd) {
+	m.Confirm = &confirmPrompt{Text: text, OnYes: onYes}
+}
+
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := m.Confirm
+	m.Confirm = nil
+	if msg.String() == "y" || msg.String() == "Y" {
+		return m, prompt.OnYes(&m)
+	}
+	return m, m.setStatus("Cancelled", false)
+}
+
+func (m Model) viewConfirm() string {
+	return m.Styles.StatusBarError.Render(" " + m.Confirm.Text + " (y/n) ")
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// EntryFilter restricts the entry list to a single feed or category.
+// The zero value shows entries from every feed.
+type EntryFilter struct {
+	FeedID     int
+	CategoryID int
+	Title      string
+}
+
+type feedNodeKind int
+
+const (
+	feedNodeAll feedNodeKind = iota
+	feedNodeCategory
+	feedNodeFeed
+)
+
+type feedNode struct {
+	Kind   feedNodeKind
+	ID     int
+	Title  string
+	Unread int
+}
+
+func (n feedNode) filter() EntryFilter {
+	switch n.Kind {
+	case feedNodeCategory:
+		return EntryFilter{CategoryID: n.ID, Title: n.Title}
+	case feedNodeFeed:
+		return EntryFilter{FeedID: n.ID, Title: n.Title}
+	}
+	return EntryFilter{}
+}
+
+func (n feedNode) matches(f EntryFilter) bool {
+	switch n.Kind {
+	case feedNodeCategory:
+		return f.CategoryID == n.ID
+	case feedNodeFeed:
+		return f.FeedID == n.ID
+	}
+	return f.CategoryID == 0 && f.FeedID == 0
+}
+
+// buildFeedTree flattens categories and their feeds into display order:
+// an "All" node first, then each category followed by its feeds.
+func buildFeedTree(categories []miniflux.Category, feeds []miniflux.Feed, counters miniflux.FeedCounters) []feedNode {
+	byCategory := make(map[int][]miniflux.Feed)
+	for _, feed := range feeds {
+		byCategory[feed.Category.ID] = append(byCategory[feed.Category.ID], feed)
+	}
+
+	categories = append([]miniflux.Category(nil), categories...)
+	sort.Slice(categories, func(i, j int) bool {
+		return strings.ToLower(categories[i].Title) < strings.ToLower(categories[j].Title)
+	})
+
+	total := 0
+	var nodes []feedNode
+	for _, category := range categories {
+		categoryFeeds := byCategory[category.ID]
+		sort.Slice(categoryFeeds, func(i, j int) bool {
+			return strings.ToLower(categoryFeeds[i].Title) < strings.ToLower(categoryFeeds[j].Title)
+		})
+
+		categoryIdx := len(nodes)
+		nodes = append(nodes, feedNode{Kind: feedNodeCategory, ID: category.ID, Title: category.Title})
+		for _, feed := range categoryFeeds {
+			unread := counters.Unreads[feed.ID]
+			nodes[categoryIdx].Unread += unread
+			nodes = append(nodes, feedNode{Kind: feedNodeFeed, ID: feed.ID, Title: feed.Title, Unread: unread})
+		}
+		total += nodes[categoryIdx].Unread
+	}
+
+	return append([]feedNode{{Kind: feedNodeAll, Title: "All", Unread: total}}, nodes...)
+}
+
+func (m Model) fetchFeedTree() tea.Msg {
+	if m.Store != nil {
+		return m.storedFeedTree()
+	}
+	categories, err := m.Client.GetCategories(m.ctx)
+	if err != nil {
+		return errorMsg(err)
+	}
+	feeds, err := m.Client.GetFeeds(m.ctx)
+	if err != nil {
+		return errorMsg(err)
+	}
+	counters, err := m.Client.GetFeedCounters(m.ctx)
+	if err != nil {
+		return errorMsg(err)
+	}
+	return FeedTreeMsg{Categories: categories, Feeds: feeds, Counters: counters}
+}
+
+func (m Model) storedFeedTree() tea.Msg {
+	categories, err := m.Store.Categories()
+	if err != nil {
+		return errorMsg(err)
+	}
+	feeds, err := m.Store.Feeds()
+	if err != nil {
+		return errorMsg(err)
+	}
+	counters, err := m.Store.Counters()
+	if err != nil {
+		return errorMsg(err)
+	}
+	return FeedTreeMsg{Categories: categories, Feeds: feeds, Counters: counters}
+}
+
+func (m Model) updateFeeds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case keyMatches(msg, m.Keys.Up):
+		if m.FeedCursor > 0 {
+			m.FeedCursor--
+		}
+	case keyMatches(msg, m.Keys.Down):
+		if m.FeedCursor < len(m.FeedTree)-1 {
+			m.FeedCursor++
+		}
+	case keyMatches(msg, m.Keys.Enter):
+		if len(m.FeedTree) > 0 {
+			return m, m.setFilter(m.FeedTree[m.FeedCursor].filter())
+		}
+	case keyMatches(msg, m.Keys.AddFeed):
+		return m, m.openSubscribe()
+	case keyMatches(msg, m.Keys.EditFeed):
+		if len(m.FeedTree) > 0 && m.FeedTree[m.FeedCursor].Kind == feedNodeCategory {
+			return m, m.openRenameCategory(m.FeedTree[m.FeedCursor])
+		}
+		return m, m.loadFeedForEdit()
+	case keyMatches(msg, m.Keys.DeleteFeed):
+		if len(m.FeedTree) > 0 && m.FeedTree[m.FeedCursor].Kind == feedNodeCategory {
+			return m, m.promptDeleteCategory(m.FeedTree[m.FeedCursor])
+		}
+		m.promptUnsubscribe()
+	case keyMatches(msg, m.Keys.AddCategory):
+		return m, m.openNewCategory()
+	case keyMatches(msg, m.Keys.SelectFeed):
+		m.toggleFeedSelection()
+	case keyMatches(msg, m.Keys.MoveFeeds):
+		return m, m.openMoveFeeds()
+	case keyMatches(msg, m.Keys.Refresh):
+		if m.Store != nil {
+			// The tree is rebuilt from the cache once the sync finishes
+			return m, m.refresh()
+		}
+		m.State = StateLoading
+		return m, m.fetchFeedTree
+	}
+	return m, nil
+}
+
+func (m Model) viewFeeds() string {
+	var s strings.Builder
+	s.WriteString(m.Styles.Title.Render("Feeds") + "\n\n")
+
+	start, end := m.listWindow(m.FeedCursor, len(m.FeedTree))
+	for i := start; i < end; i++ {
+		node := m.FeedTree[i]
+		cursor := " "
+		style := m.Styles.Read
+		if node.Unread > 0 {
+			style = m.Styles.Unread
+		}
+		if m.FeedCursor == i {
+			cursor = ">"
+			style = m.Styles.Selected
+		}
+
+		indent := ""
+		if node.Kind == feedNodeFeed {
+			indent = "  "
+			if m.FeedSelection[node.ID] {
+				indent = "+ "
+			}
+		}
+
+		line := fmt.Sprintf("%s %s%s (%d)", cursor, indent, truncate(node.Title, 60), node.Unread)
+		s.WriteString(style.Render(line) + "\n")
+	}
+
+	return s.String()
+}
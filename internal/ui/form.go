@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fieldKind int
+
+const (
+	fieldText fieldKind = iota
+	fieldChoice
+	fieldToggle
+)
+
+// formField is one row of a form: free text, one of a list of choices or
+// an on/off switch.
+type formField struct {
+	Label   string
+	Kind    fieldKind
+	Input   textinput.Model
+	Choices []string
+	Choice  int
+	On      bool
+}
+
+func textField(label, value string) formField {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.CharLimit = 2000
+	ti.SetValue(value)
+	return formField{Label: label, Kind: fieldText, Input: ti}
+}
+
+func choiceField(label string, choices []string, selected int) formField {
+	return formField{Label: label, Kind: fieldChoice, Choices: choices, Choice: selected}
+}
+
+func toggleField(label string, on bool) formField {
+	return formField{Label: label, Kind: fieldToggle, On: on}
+}
+
+func (f formField) Value() string {
+	return strings.TrimSpace(f.Input.Value())
+}
+
+// form is a small editor shown in place of the current screen. Its keys
+// are fixed, like the search prompt's: tab and the arrow keys move
+// between fields, left and right change a choice, space flips a switch,
+// enter submits and esc cancels.
+type form struct {
+	Title  string
+	Fields []formField
+	Focus  int
+	// Busy is set while the submitted form waits on the server.
+	Busy     bool
+	OnSubmit func(m *Model, f *form) tea.Cmd
+	// Return is the state to go back to when the form closes.
+	Return State
+}
+
+func (m *Model) openForm(f *form) tea.Cmd {
+	f.Return = m.State
+	if f.Return == StateForm && m.Form != nil {
+		f.Return = m.Form.Return
+	}
+	m.Form = f
+	m.State = StateForm
+	return f.focus(0)
+}
+
+func (m *Model) closeForm() {
+	if m.Form != nil {
+		m.State = m.Form.Return
+		m.Form = nil
+	}
+}
+
+func (f *form) focus(i int) tea.Cmd {
+	if n := len(f.Fields); n > 0 {
+		i = (i + n) % n
+	}
+	for j := range f.Fields {
+		f.Fields[j].Input.Blur()
+	}
+	f.Focus = i
+	if f.Fields[i].Kind == fieldText {
+		return f.Fields[i].Input.Focus()
+	}
+	return nil
+}
+
+func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := m.Form
+	if msg.String() == "esc" {
+		m.closeForm()
+		return m, m.setStatus("Cancelled", false)
+	}
+	if f.Busy {
+		return m, nil
+	}
+	field := &f.Fields[f.Focus]
+
+	switch msg.String() {
+	case "enter":
+		f.Busy = true
+		return m, f.OnSubmit(&m, f)
+	case "tab", "down":
+		return m, f.focus(f.Focus + 1)
+	case "shift+tab", "up":
+		return m, f.focus(f.Focus - 1)
+	}
+
+	switch field.Kind {
+	case fieldChoice:
+		if len(field.Choices) == 0 {
+			break
+		}
+		switch msg.String() {
+		case "left", "h":
+			field.Choice = (field.Choice + len(field.Choices) - 1) % len(field.Choices)
+		case "right", "l", " ":
+			field.Choice = (field.Choice + 1) % len(field.Choices)
+		}
+	case fieldToggle:
+		switch msg.String() {
+		case "left", "right", "h", "l", " ":
+			field.On = !field.On
+		}
+	case fieldText:
+		var cmd tea.Cmd
+		field.Input, cmd = field.Input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) viewForm() string {
+	f := m.Form
+	var s strings.Builder
+	s.WriteString(m.Styles.Title.Render(f.Title) + "\n\n")
+
+	width := 0
+	for _, field := range f.Fields {
+		width = max(width, len(field.Label))
+	}
+	for i, field := range f.Fields {
+		label := fmt.Sprintf("%-*s  ", width, field.Label)
+		var value string
+		switch field.Kind {
+		case fieldText:
+			value = field.Input.View()
+		case fieldChoice:
+			if len(field.Choices) > 0 {
+				value = "< " + field.Choices[field.Choice] + " >"
+			}
+		case fieldToggle:
+			value = "[ ]"
+			if field.On {
+				value = "[x]"
+			}
+		}
+		if i == f.Focus {
+			s.WriteString(m.Styles.Selected.Render("> "+label) + " " + value + "\n")
+		} else {
+			s.WriteString(m.Styles.Base.Render("  "+label) + " " + value + "\n")
+		}
+	}
+
+	s.WriteString("\n" + m.Styles.Dim.Render("  tab next field · ←/→ change · enter save · esc cancel") + "\n")
+	return s.String()
+}
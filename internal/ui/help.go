@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stateKeyMap is the subset of a KeyMap that applies in one State.
+type stateKeyMap struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (k stateKeyMap) ShortHelp() []key.Binding  { return k.short }
+func (k stateKeyMap) FullHelp() [][]key.Binding { return k.full }
+
+// ForState returns the bindings that do something in state s, grouped
+// into help columns.
+func (k KeyMap) ForState(s State) help.KeyMap {
+	short := []key.Binding{k.Help, k.Quit}
+	switch s {
+	case StateList:
+		return stateKeyMap{short: short, full: [][]key.Binding{
+			{k.Up, k.Down, k.Enter, k.Back},
+			{k.NextView, k.PrevView, k.Feeds, k.Search, k.Profiles},
+			{k.Refresh, k.ToggleReadList, k.ToggleStar, k.Save, k.OpenBrowser},
+			{k.MarkAllRead, k.Undo, k.Help, k.Quit},
+		}}
+	case StateReading:
+		return stateKeyMap{short: short, full: [][]key.Binding{
+			{k.Up, k.Down, k.Back},
+			{k.ToggleRead, k.ToggleStar, k.Save, k.OpenBrowser, k.FetchOriginal},
+			{k.NextMatch, k.PrevMatch, k.Help, k.Quit},
+		}}
+	case StateFeeds:
+		return stateKeyMap{short: short, full: [][]key.Binding{
+			{k.Up, k.Down, k.Enter, k.Back},
+			{k.AddFeed, k.EditFeed, k.DeleteFeed},
+			{k.AddCategory, k.SelectFeed, k.MoveFeeds},
+			{k.Refresh, k.Help, k.Quit},
+		}}
+	case StateProfiles:
+		return stateKeyMap{short: short, full: [][]key.Binding{
+			{k.Up, k.Down, k.Enter, k.Back},
+			{k.Help, k.Quit},
+		}}
+	}
+	return stateKeyMap{short: short, full: [][]key.Binding{{k.Refresh, k.Quit}}}
+}
+
+func (m Model) viewHelp() string {
+	m.Help.ShowAll = true
+	box := m.Styles.HelpBox.Render(m.Styles.Title.Render("Keys") + "\n\n" + m.Help.View(m.Keys.ForState(m.State)))
+	return lipgloss.Place(m.Viewport.Width, m.Viewport.Height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func newHelp() help.Model {
+	h := help.New()
+	h.FullSeparator = "    "
+	return h
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Up             key.Binding
@@ -16,61 +20,84 @@ type KeyMap struct {
 	Help           key.Binding
 	Save           key.Binding
 	OpenBrowser    key.Binding
+	Feeds          key.Binding
+	NextView       key.Binding
+	PrevView       key.Binding
+	Search         key.Binding
+	NextMatch      key.Binding
+	PrevMatch      key.Binding
+	FetchOriginal  key.Binding
+	Undo           key.Binding
+	Profiles       key.Binding
+	AddFeed        key.Binding
+	EditFeed       key.Binding
+	DeleteFeed     key.Binding
+	AddCategory    key.Binding
+	SelectFeed     key.Binding
+	MoveFeeds      key.Binding
+}
+
+// NewKeyMap builds a KeyMap from action names to keys, as returned by
+// config.Config.KeyBindings.
+func NewKeyMap(bindings map[string][]string) KeyMap {
+	bind := func(action, desc string) key.Binding {
+		keys := bindings[action]
+		return key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(helpKeys(keys), desc),
+		)
+	}
+
+	return KeyMap{
+		Up:             bind("up", "move up"),
+		Down:           bind("down", "move down"),
+		Enter:          bind("enter", "open entry"),
+		Back:           bind("back", "back"),
+		Quit:           bind("quit", "quit"),
+		Refresh:        bind("refresh", "refresh"),
+		ToggleRead:     bind("toggle_read", "toggle read"),
+		ToggleReadList: bind("toggle_read_list", "toggle read"),
+		ToggleStar:     bind("toggle_star", "toggle star"),
+		MarkAllRead:    bind("mark_all_read", "mark all read"),
+		Help:           bind("help", "toggle help"),
+		Save:           bind("save", "save"),
+		OpenBrowser:    bind("open_browser", "open in browser"),
+		Feeds:          bind("feeds", "feeds"),
+		NextView:       bind("next_view", "next view"),
+		PrevView:       bind("prev_view", "previous view"),
+		Search:         bind("search", "search"),
+		NextMatch:      bind("next_match", "next match"),
+		PrevMatch:      bind("prev_match", "previous match"),
+		FetchOriginal:  bind("fetch_original", "fetch original"),
+		Undo:           bind("undo", "undo mark all read"),
+		Profiles:       bind("profiles", "switch profile"),
+		AddFeed:        bind("add_feed", "subscribe"),
+		EditFeed:       bind("edit_feed", "edit feed/rename category"),
+		DeleteFeed:     bind("delete_feed", "unsubscribe/delete category"),
+		AddCategory:    bind("add_category", "new category"),
+		SelectFeed:     bind("select_feed", "select feed"),
+		MoveFeeds:      bind("move_feeds", "move to category"),
+	}
 }
 
-var Keys = KeyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "move down"),
-	),
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "open entry"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("esc", "b"),
-		key.WithHelp("esc/b", "back"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q/ctrl+c", "quit"),
-	),
-	Refresh: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "refresh"),
-	),
-	ToggleRead: key.NewBinding(
-		key.WithKeys("u"),
-		key.WithHelp("u", "toggle read"),
-	),
-	ToggleReadList: key.NewBinding(
-		key.WithKeys("m"),
-		key.WithHelp("m", "toggle read"),
-	),
-	ToggleStar: key.NewBinding(
-		key.WithKeys("s"),
-		key.WithHelp("s", "toggle star"),
-	),
-	MarkAllRead: key.NewBinding(
-		key.WithKeys("A"),
-		key.WithHelp("A", "mark all read"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
-	Save: key.NewBinding(
-		key.WithKeys("e"),
-		key.WithHelp("e", "save"),
-	),
-	OpenBrowser: key.NewBinding(
-		key.WithKeys("o"),
-		key.WithHelp("o", "open in browser"),
-	),
+// helpKeys formats keys for the help overlay, e.g. "↑/k".
+func helpKeys(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			labels[i] = "↑"
+		case "down":
+			labels[i] = "↓"
+		case "left":
+			labels[i] = "←"
+		case "right":
+			labels[i] = "→"
+		default:
+			labels[i] = k
+		}
+	}
+	return strings.Join(labels, "/")
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -80,8 +107,11 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Enter, k.Back},
-		{k.Up, k.Down, k.Enter, k.Back},
-		{k.Refresh, k.ToggleReadList, k.ToggleStar, k.MarkAllRead},
-		{k.Save, k.OpenBrowser, k.Quit},
+		{k.Refresh, k.ToggleReadList, k.ToggleStar, k.MarkAllRead, k.Undo},
+		{k.Save, k.OpenBrowser, k.Feeds, k.NextView, k.PrevView},
+		{k.Search, k.NextMatch, k.PrevMatch, k.FetchOriginal},
+		{k.Profiles, k.AddFeed, k.EditFeed, k.DeleteFeed},
+		{k.AddCategory, k.SelectFeed, k.MoveFeeds},
+		{k.Help, k.Quit},
 	}
 }
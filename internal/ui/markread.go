@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// undoWindow is how long a mark-all-read can be undone.
+const undoWindow = 10 * time.Second
+
+// undoState remembers the entries a mark-all-read changed so they can be
+// flipped back to unread.
+type undoState struct {
+	EntryIDs []int
+	seq      int
+}
+
+type undoExpiredMsg struct {
+	seq int
+}
+
+// promptMarkAllRead asks for confirmation before marking the current
+// feed or category read on the server, or, without a filter, every
+// unread entry in the current list, including pages not loaded yet.
+func (m *Model) promptMarkAllRead() tea.Cmd {
+	filter := m.Filter
+	if m.Search == "" && (filter.FeedID != 0 || filter.CategoryID != 0) {
+		m.confirm(fmt.Sprintf("Mark everything in %q as read?", filter.Title), func(m *Model) tea.Cmd {
+			return m.markFilterRead(filter)
+		})
+		return nil
+	}
+
+	q := entryQuery(m.CurrentView, filter, m.Search, unreadBatch, 0)
+	if len(q.Status) > 0 && !slices.Contains(q.Status, miniflux.ReadStatusUnread) {
+		return m.setStatus("Nothing to mark as read", false)
+	}
+	q.Status = []miniflux.ReadStatus{miniflux.ReadStatusUnread}
+	text := fmt.Sprintf("Mark every unread entry in the %s view as read?", m.CurrentView)
+	if m.Search != "" {
+		text = fmt.Sprintf("Mark every unread entry matching %q as read?", m.Search)
+	}
+	m.confirm(text, func(m *Model) tea.Cmd {
+		client, ctx := m.Client, m.ctx
+		return func() tea.Msg {
+			ids, err := unreadEntryIDs(ctx, client, q)
+			if err != nil {
+				return errorMsg(err)
+			}
+			if len(ids) > 0 {
+				if err := client.MarkAllAsRead(ctx, ids); err != nil {
+					return errorMsg(err)
+				}
+			}
+			return MarkedAllReadMsg{EntryIDs: ids}
+		}
+	})
+	return nil
+}
+
+// markFilterRead marks a whole feed or category read. The unread entry IDs
+// are collected first so the change can be undone.
+func (m *Model) markFilterRead(filter EntryFilter) tea.Cmd {
+	client, ctx := m.Client, m.ctx
+	return func() tea.Msg {
+		ids, err := unreadEntryIDs(ctx, client, ViewUnread.query(filter, unreadBatch, 0))
+		if err != nil {
+			return errorMsg(err)
+		}
+		if filter.FeedID != 0 {
+			err = client.MarkFeedAsRead(ctx, filter.FeedID)
+		} else {
+			err = client.MarkCategoryAsRead(ctx, filter.CategoryID)
+		}
+		if err != nil {
+			return errorMsg(err)
+		}
+		return MarkedAllReadMsg{EntryIDs: ids}
+	}
+}
+
+// unreadBatch is the page size used to collect entry IDs.
+const unreadBatch = 500
+
+// unreadEntryIDs pages through q and returns the IDs of every match.
+func unreadEntryIDs(ctx context.Context, client *miniflux.Client, q miniflux.EntryQuery) ([]int, error) {
+	var ids []int
+	for {
+		entries, total, err := client.GetEntries(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			ids = append(ids, entry.ID)
+		}
+		q.Offset += len(entries)
+		if len(entries) == 0 || q.Offset >= total {
+			return ids, nil
+		}
+	}
+}
+
+func (m *Model) markedAllRead(ids []int) tea.Cmd {
+	if len(ids) == 0 {
+		return m.setStatus("No unread entries to mark", false)
+	}
+	for _, id := range ids {
+		m.setLocalStatus(id, miniflux.ReadStatusRead)
+	}
+	m.storeStatus(ids, miniflux.ReadStatusRead)
+	m.undoSeq++
+	seq := m.undoSeq
+	m.Undo = &undoState{EntryIDs: ids, seq: seq}
+
+	return tea.Batch(
+		m.setStatusFor(fmt.Sprintf("Marked %d entries as read - press %s to undo", len(ids), m.Keys.Undo.Help().Key), false, undoWindow),
+		tea.Tick(undoWindow, func(time.Time) tea.Msg {
+			return undoExpiredMsg{seq: seq}
+		}),
+		m.fetchUnreadCount,
+	)
+}
+
+func (m *Model) undoMarkAllRead() tea.Cmd {
+	if m.Undo == nil {
+		return m.setStatus("Nothing to undo", false)
+	}
+	ids := m.Undo.EntryIDs
+	m.Undo = nil
+	for _, id := range ids {
+		m.setLocalStatus(id, miniflux.ReadStatusUnread)
+	}
+	client, ctx := m.Client, m.ctx
+	return func() tea.Msg {
+		if err := client.ChangeEntryReadStatus(ctx, ids, miniflux.ReadStatusUnread); err != nil {
+			return MarkFailedMsg{EntryIDs: ids, Status: miniflux.ReadStatusRead, Err: err}
+		}
+		return UndoneMsg{EntryIDs: ids}
+	}
+}
+
+// undone stores the undo once the server has confirmed it.
+func (m *Model) undone(ids []int) tea.Cmd {
+	m.storeStatus(ids, miniflux.ReadStatusUnread)
+	return tea.Batch(m.setStatus(fmt.Sprintf("Restored %d entries to unread", len(ids)), false), m.fetchUnreadCount)
+}
+
+// markFailed reverts the local status change of a failed undo.
+func (m *Model) markFailed(msg MarkFailedMsg) tea.Cmd {
+	for _, id := range msg.EntryIDs {
+		m.setLocalStatus(id, msg.Status)
+	}
+	text := fmt.Sprintf("Could not mark %d entries %s: %s - reverted", len(msg.EntryIDs), msg.Status.Toggle(), m.errorText(msg.Err))
+	return tea.Batch(m.setStatus(text, true), m.fetchUnreadCount)
+}
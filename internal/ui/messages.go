@@ -1,19 +1,100 @@
 package ui
 
 import (
+	"github.com/slatkin/goflux/pkg/config"
 	"github.com/slatkin/goflux/pkg/miniflux"
+	"github.com/slatkin/goflux/pkg/store"
 )
 
 type ErrorMsg error
 
-type EntriesMsg []miniflux.FeedEntry
+// EntriesMsg carries one page of entries. A zero Offset replaces the
+// current list; anything else appends to it.
+type EntriesMsg struct {
+	View    View
+	Filter  EntryFilter
+	Search  string
+	Entries []miniflux.FeedEntry
+	Total   int
+	Offset  int
+	// Reload replaces the list but keeps the cursor on the same entry.
+	Reload bool
+	// Cached is set when a search was answered from the offline store
+	// because the server could not be reached.
+	Cached bool
+}
+
+type FeedTreeMsg struct {
+	Categories []miniflux.Category
+	Feeds      []miniflux.Feed
+	Counters   miniflux.FeedCounters
+}
+
+type UnreadCountMsg int
+
+// MarkedAllReadMsg reports the entries a mark-all-read changed.
+type MarkedAllReadMsg struct {
+	EntryIDs []int
+}
+
+// ProfileResolvedMsg carries the config of a profile being switched to,
+// with its credentials resolved.
+type ProfileResolvedMsg struct {
+	Name   string
+	Config config.Config
+	Err    error
+}
+
+// MarkFailedMsg reports a bulk status change the server rejected. The
+// entries are put back to Status, which they had before.
+type MarkFailedMsg struct {
+	EntryIDs []int
+	Status   miniflux.ReadStatus
+	Err      error
+}
 
 type EntryContentMsg struct {
 	EntryID int
 	Content string
 }
 
-type ActionDoneMsg struct {
-	Action  string
-	Success bool
+// UndoneMsg reports that the server restored the entries of an undone
+// mark-all-read to unread.
+type UndoneMsg struct {
+	EntryIDs []int
+}
+
+// SyncedMsg reports the end of an offline store sync.
+type SyncedMsg struct {
+	Result store.SyncResult
+	Err    error
+	gen    int
+}
+
+// ReplayedMsg reports the end of an outbox replay.
+type ReplayedMsg struct {
+	Result store.ReplayResult
+	Err    error
+	gen    int
+}
+
+// DiscoveredMsg lists the feeds found at a website.
+type DiscoveredMsg struct {
+	URL           string
+	Subscriptions []miniflux.Subscription
+}
+
+// FeedLoadedMsg carries a feed's settings for editing.
+type FeedLoadedMsg struct {
+	Feed miniflux.Feed
+}
+
+// FeedsChangedMsg reports a subscription or category change made on the
+// server. Category is set when a category was renamed or deleted, and
+// FeedID when a feed was unsubscribed from.
+type FeedsChangedMsg struct {
+	Text     string
+	Category miniflux.Category
+	FeedID   int
+	Deleted  bool
 }
@@ -1,19 +1,34 @@
 package ui
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jaytaylor/html2text"
 	"github.com/pkg/browser"
+	"github.com/slatkin/goflux/internal/client"
 	"github.com/slatkin/goflux/pkg/config"
 	"github.com/slatkin/goflux/pkg/miniflux"
+	"github.com/slatkin/goflux/pkg/store"
+)
+
+const (
+	pageSize = 50
+	// loadAhead is how close the cursor may get to the end of the list
+	// before the next page is requested.
+	loadAhead = 10
 )
 
 type State int
@@ -22,93 +37,337 @@ const (
 	StateLoading State = iota
 	StateList
 	StateReading
+	StateFeeds
+	StateSearch
+	StateProfiles
+	StateForm
 	StateError
 )
 
 type Model struct {
 	Client *miniflux.Client
+	// Store is the offline cache, nil when it is disabled or unavailable.
+	Store  *store.Store
 	Config config.Config
-
-	State    State
-	Entries  []miniflux.FeedEntry
-	Cursor   int
-	Offset   int
-	Selected *miniflux.FeedEntry
+	Keys   KeyMap
+	Styles Styles
+
+	State       State
+	CurrentView View
+	entryList
+	// SavedLists holds the list state of the views not currently shown.
+	SavedLists [viewCount]entryList
+	Offset     int
+	Selected   *miniflux.FeedEntry
+	Filter     EntryFilter
+
+	// Search is the active search query; while set, the list shows
+	// search results instead of CurrentView.
+	Search      string
+	SearchInput textinput.Model
+	MatchLines  []int
+	MatchIndex  int
+
+	FeedTree   []feedNode
+	FeedCursor int
+	// FeedSelection holds the IDs of feeds marked for a bulk move.
+	FeedSelection map[int]bool
+
+	ProfileCursor int
+
+	// Form is the editor shown in StateForm.
+	Form *form
 
 	Viewport viewport.Model
 	Help     help.Model
+	ShowHelp bool
 
 	Err error
+
+	Spinner         spinner.Model
+	StatusText      string
+	StatusIsError   bool
+	statusSeq       int
+	Unread          int
+	FetchingContent bool
+	Syncing         bool
+	// Outbox holds read, star and save actions until the server has them;
+	// Queued is how many are waiting.
+	Outbox     *store.Outbox
+	Queued     int
+	Flushing   bool
+	flushAgain bool
+	Confirm    *confirmPrompt
+	Undo       *undoState
+	undoSeq    int
+	// ready is set once the first entry list loads; failures before then
+	// are shown full screen.
+	ready bool
+	// profileGen counts profile switches. Sync and replay results tagged
+	// with an older generation belong to the previous server and are
+	// dropped.
+	profileGen int
+
+	// ctx is cancelled on quit; cancelLoad, cancelContent, cancelSync and
+	// cancelFlush abort the in-flight entry list, original content, cache
+	// sync and outbox replay.
+	ctx           context.Context
+	cancel        context.CancelFunc
+	cancelLoad    context.CancelFunc
+	cancelContent context.CancelFunc
+	cancelSync    context.CancelFunc
+	cancelFlush   context.CancelFunc
 }
 
 func NewModel(cfg config.Config) Model {
-	client := miniflux.NewClient(cfg.ServerUrl, cfg.ApiKey, cfg.AllowInvalidCerts)
 	vp := viewport.New(0, 0)
 	vp.Style = lipgloss.NewStyle().Padding(1, 2)
-
-	return Model{
-		Client:   client,
-		Config:   cfg,
-		State:    StateLoading,
-		Viewport: vp,
-		Help:     help.New(),
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m := Model{
+		ctx:         ctx,
+		cancel:      cancel,
+		Client:      client.New(cfg),
+		Keys:        NewKeyMap(cfg.KeyBindings()),
+		Styles:      NewStyles(cfg.Theme),
+		Config:      cfg,
+		State:       StateLoading,
+		Viewport:    vp,
+		Help:        newHelp(),
+		SearchInput: newSearchInput(),
+		Spinner:     newSpinner(),
 	}
+	warnings := cfg.Warnings
+	if s, err := openStore(cfg); err != nil {
+		warnings = append(warnings, "offline cache disabled: "+err.Error())
+	} else if s != nil {
+		m.Store = s
+		// Init runs on a copy, so the sync it starts is flagged here
+		m.Syncing = true
+	}
+	m.Outbox = newOutbox(m.Store)
+	m.countQueued()
+	if len(warnings) > 0 {
+		// Stays up until the next status message replaces it
+		m.StatusText = warnings[0]
+		m.StatusIsError = true
+	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.EnterAltScreen,
-		m.fetchUnreadEntries,
+		m.Spinner.Tick,
+		m.load(),
 	)
 }
 
-func (m Model) fetchUnreadEntries() tea.Msg {
-	entries, err := m.Client.GetUnreadEntries(50, 0) // TODO: Pagination
+func (m Model) fetchUnreadCount() tea.Msg {
+	if m.Store != nil {
+		n, err := m.Store.UnreadCount()
+		if err != nil {
+			return errorMsg(err)
+		}
+		return UnreadCountMsg(n)
+	}
+	counters, err := m.Client.GetFeedCounters(m.ctx)
 	if err != nil {
-		return ErrorMsg(err)
+		return errorMsg(err)
+	}
+	total := 0
+	for _, n := range counters.Unreads {
+		total += n
 	}
-	return EntriesMsg(entries)
+	return UnreadCountMsg(total)
 }
 
-func (m Model) fetchContent(entryID int) tea.Cmd {
+// errorMsg reports err to the UI, dropping errors from requests that
+// were cancelled on purpose.
+func errorMsg(err error) tea.Msg {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return ErrorMsg(err)
+}
+
+// fetchEntries loads a page of the current list, cancelling any entry
+// load still in flight.
+func (m *Model) fetchEntries(offset int) tea.Cmd {
+	return m.loadEntries(offset, pageSize, false)
+}
+
+// reloadEntries reloads everything loaded so far in the current list,
+// keeping the cursor on the same entry.
+func (m *Model) reloadEntries() tea.Cmd {
+	return m.loadEntries(0, max(len(m.Entries), pageSize), true)
+}
+
+// loadEntries reads from the offline store when there is one and from
+// the server otherwise. Searches always go to the server, which sees
+// every entry; the store only answers them when the server is
+// unreachable.
+func (m *Model) loadEntries(offset, limit int, reload bool) tea.Cmd {
+	m.cancelLoads()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelLoad = cancel
+
+	view, filter, search := m.CurrentView, m.Filter, m.Search
+	client, s := m.Client, m.Store
 	return func() tea.Msg {
-		content, err := m.Client.FetchOriginalContent(entryID)
+		defer cancel()
+		q := entryQuery(view, filter, search, limit, offset)
+		var entries []miniflux.FeedEntry
+		var total int
+		var err error
+		cached := false
+		if s != nil && search == "" {
+			entries, total, err = s.Entries(q)
+		} else {
+			entries, total, err = client.GetEntries(ctx, q)
+			if err != nil && s != nil && ctx.Err() == nil && isOffline(err) {
+				entries, total, err = s.Entries(q)
+				cached = true
+			}
+		}
 		if err != nil {
-			return ErrorMsg(err)
+			return errorMsg(err)
 		}
-		return EntryContentMsg{EntryID: entryID, Content: content}
+		return EntriesMsg{View: view, Filter: filter, Search: search, Entries: entries, Total: total, Offset: offset, Reload: reload, Cached: cached}
 	}
 }
 
-func (m Model) toggleReadStatus(entryID int, currentStatus miniflux.ReadStatus) tea.Cmd {
-	return func() tea.Msg {
-		newStatus := currentStatus.Toggle()
-		err := m.Client.ChangeEntryReadStatus([]int{entryID}, newStatus)
-		if err != nil {
-			return ErrorMsg(err)
+// switchView stashes the current list and restores the one for v,
+// loading it from the server the first time it is shown.
+func (m *Model) switchView(v View) tea.Cmd {
+	m.cancelLoads()
+	if m.Search != "" {
+		// Leaving a search; the view's own list is still stashed
+		m.Search = ""
+	} else {
+		m.SavedLists[m.CurrentView] = m.entryList
+	}
+	m.CurrentView = v
+	m.entryList = m.SavedLists[v]
+	if m.Loaded {
+		return nil
+	}
+	m.State = StateLoading
+	return m.fetchEntries(0)
+}
+
+// cancelLoads aborts the in-flight entry load, if any.
+func (m *Model) cancelLoads() {
+	if m.cancelLoad != nil {
+		m.cancelLoad()
+		m.cancelLoad = nil
+	}
+	m.LoadingMore = false
+}
+
+// setFilter applies a feed or category filter, discarding every view's
+// loaded entries since they no longer match.
+func (m *Model) setFilter(f EntryFilter) tea.Cmd {
+	m.Filter = f
+	m.Search = ""
+	m.entryList = entryList{}
+	m.SavedLists = [viewCount]entryList{}
+	m.State = StateLoading
+	return m.fetchEntries(0)
+}
+
+// loadMoreIfNeeded requests the next page once the cursor nears the end
+// of the loaded entries.
+func (m *Model) loadMoreIfNeeded() tea.Cmd {
+	if m.LoadingMore || m.NextOffset >= m.Total || m.Cursor < len(m.Entries)-loadAhead {
+		return nil
+	}
+	cmd := m.fetchEntries(m.NextOffset)
+	m.LoadingMore = true
+	return cmd
+}
+
+// appendEntries adds a page to the list, skipping entries already loaded.
+// Offsets shift when new entries arrive between page loads, so the same
+// entry can show up on two consecutive pages.
+func (m *Model) appendEntries(entries []miniflux.FeedEntry) {
+	seen := make(map[int]bool, len(m.Entries))
+	for _, entry := range m.Entries {
+		seen[entry.ID] = true
+	}
+	for _, entry := range entries {
+		if !seen[entry.ID] {
+			seen[entry.ID] = true
+			m.Entries = append(m.Entries, entry)
 		}
-		return ActionDoneMsg{Action: "read_toggle", Success: true}
 	}
 }
 
-func (m Model) markAsRead(entryID int) tea.Cmd {
+func (m *Model) fetchContent(entryID int) tea.Cmd {
+	m.cancelContentFetch()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelContent = cancel
+	m.FetchingContent = true
+
+	client, save := m.Client, m.Config.SaveOriginalContent
 	return func() tea.Msg {
-		err := m.Client.ChangeEntryReadStatus([]int{entryID}, miniflux.ReadStatusRead)
+		defer cancel()
+		content, err := client.FetchOriginalContent(ctx, entryID, save)
 		if err != nil {
-			return ErrorMsg(err)
+			return errorMsg(err)
 		}
-		return ActionDoneMsg{Action: "mark_read", Success: true}
+		return EntryContentMsg{EntryID: entryID, Content: content}
 	}
 }
 
-func (m Model) saveEntry(entryID int) tea.Cmd {
-	return func() tea.Msg {
-		err := m.Client.SaveEntry(entryID)
-		if err != nil {
-			return ErrorMsg(err)
+func (m *Model) cancelContentFetch() {
+	if m.cancelContent != nil {
+		m.cancelContent()
+		m.cancelContent = nil
+	}
+	m.FetchingContent = false
+}
+
+// setLocalStatus updates an entry's status in the list and reader and
+// keeps the unread count in step.
+func (m *Model) setLocalStatus(entryID int, status miniflux.ReadStatus) {
+	var prev miniflux.ReadStatus
+	if m.Selected != nil && m.Selected.ID == entryID {
+		prev = m.Selected.Status
+		m.Selected.Status = status
+	}
+	for i := range m.Entries {
+		if m.Entries[i].ID == entryID {
+			prev = m.Entries[i].Status
+			m.Entries[i].Status = status
 		}
-		return ActionDoneMsg{Action: "save_entry", Success: true}
 	}
+	switch {
+	case prev == miniflux.ReadStatusUnread && status == miniflux.ReadStatusRead:
+		m.Unread--
+	case prev == miniflux.ReadStatusRead && status == miniflux.ReadStatusUnread:
+		m.Unread++
+	}
+}
+
+// readToggle is the action that flips an entry with the given status.
+func readToggle(status miniflux.ReadStatus) store.ActionKind {
+	if status == miniflux.ReadStatusUnread {
+		return store.ActionRead
+	}
+	return store.ActionUnread
+}
+
+func (m *Model) saveEntry(entry miniflux.FeedEntry) tea.Cmd {
+	return tea.Batch(m.queueAction(store.ActionSave, entry), m.setStatus("Saved entry", false))
+}
+
+func (m *Model) toggleStar(entry miniflux.FeedEntry) tea.Cmd {
+	kind, text := store.ActionStar, "Starred entry"
+	if entry.Starred {
+		kind, text = store.ActionUnstar, "Removed star"
+	}
+	return tea.Batch(m.queueAction(kind, entry), m.setStatus(text, false))
 }
 
 func openUrl(url string) tea.Cmd {
@@ -118,20 +377,60 @@ func openUrl(url string) tea.Cmd {
 	}
 }
 
+func (m *Model) quit() tea.Cmd {
+	m.cancel()
+	m.closeStore()
+	return tea.Quit
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.Confirm != nil {
+			return m.updateConfirm(msg)
+		}
+		if m.State == StateSearch {
+			return m.updateSearchPrompt(msg)
+		}
+		if m.State == StateForm {
+			return m.updateForm(msg)
+		}
+		if m.ShowHelp {
+			// Any key other than quit just closes the overlay
+			m.ShowHelp = false
+			if !keyMatches(msg, m.Keys.Quit) {
+				return m, nil
+			}
+		}
+
 		switch {
-		case keyMatches(msg, Keys.Quit):
-			return m, tea.Quit
-		case keyMatches(msg, Keys.Back):
-			if m.State == StateReading {
+		case keyMatches(msg, m.Keys.Quit):
+			cmd := m.confirmLosingQueued("Quit", func(m *Model) tea.Cmd {
+				return m.quit()
+			})
+			return m, cmd
+		case keyMatches(msg, m.Keys.Help):
+			m.ShowHelp = true
+			return m, nil
+		case m.State == StateError && keyMatches(msg, m.Keys.Refresh):
+			m.State = StateLoading
+			return m, m.refresh()
+		case keyMatches(msg, m.Keys.Back):
+			switch m.State {
+			case StateReading:
+				m.cancelContentFetch()
 				m.State = StateList
 				m.Viewport.SetContent("") // Clear content to save memory? Or keep it.
 				// m.Viewport.GotoTop() // Reset position?
+			case StateFeeds, StateProfiles:
+				m.State = StateList
+			case StateList:
+				if m.Search != "" {
+					return m, m.exitSearch()
+				}
 			}
 			return m, nil
 		}
@@ -139,54 +438,68 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.State {
 		case StateList:
 			switch {
-			case keyMatches(msg, Keys.Up):
+			case keyMatches(msg, m.Keys.Up):
 				if m.Cursor > 0 {
 					m.Cursor--
 				}
-			case keyMatches(msg, Keys.Down):
+			case keyMatches(msg, m.Keys.Down):
 				if m.Cursor < len(m.Entries)-1 {
 					m.Cursor++
 				}
-			case keyMatches(msg, Keys.Enter):
+				return m, m.loadMoreIfNeeded()
+			case keyMatches(msg, m.Keys.Enter):
 				if len(m.Entries) > 0 {
 					m.Selected = &m.Entries[m.Cursor]
 					m.State = StateReading
 
+					if m.Selected.OriginalContent == "" && m.Config.Feed(m.Selected.FeedID).FetchOriginalContent {
+						cmds = append(cmds, m.fetchContent(m.Selected.ID))
+					}
+
 					// Auto-mark as read if unread
 					if m.Selected.Status == miniflux.ReadStatusUnread {
-						m.Selected.Status = miniflux.ReadStatusRead
-						m.Entries[m.Cursor].Status = miniflux.ReadStatusRead
-						m.Viewport.SetContent(renderEntryContent(m.Selected, m.Viewport.Width))
+						cmds = append(cmds, m.queueAction(store.ActionRead, *m.Selected))
+						m.renderReader()
 						m.Viewport.GotoTop()
-						return m, m.markAsRead(m.Selected.ID)
+						return m, tea.Batch(cmds...)
 					}
 
 					// Load content if not present or just show what we have
 					// Miniflux usually sends content in list, but we might want original
-					m.Viewport.SetContent(renderEntryContent(m.Selected, m.Viewport.Width))
+					m.renderReader()
 					m.Viewport.GotoTop()
 				}
-			case keyMatches(msg, Keys.Refresh):
+			case keyMatches(msg, m.Keys.Refresh):
+				return m, m.refresh()
+			case keyMatches(msg, m.Keys.NextView):
+				return m, m.switchView(m.CurrentView.Next())
+			case keyMatches(msg, m.Keys.PrevView):
+				return m, m.switchView(m.CurrentView.Prev())
+			case keyMatches(msg, m.Keys.Feeds):
 				m.State = StateLoading
-				return m, m.fetchUnreadEntries
-			case keyMatches(msg, Keys.ToggleReadList):
+				return m, m.fetchFeedTree
+			case keyMatches(msg, m.Keys.Search):
+				return m, m.openSearchPrompt()
+			case keyMatches(msg, m.Keys.MarkAllRead):
+				return m, m.promptMarkAllRead()
+			case keyMatches(msg, m.Keys.Undo):
+				return m, m.undoMarkAllRead()
+			case keyMatches(msg, m.Keys.Profiles):
+				return m, m.openProfiles()
+			case keyMatches(msg, m.Keys.ToggleReadList):
 				if len(m.Entries) > 0 {
 					entry := m.Entries[m.Cursor]
-					// Update locally immediately for UI responsiveness
-					if entry.Status == miniflux.ReadStatusRead {
-						m.Entries[m.Cursor].Status = miniflux.ReadStatusUnread
-					} else {
-						m.Entries[m.Cursor].Status = miniflux.ReadStatusRead
-					}
-					// Send API request
-					return m, m.toggleReadStatus(entry.ID, entry.Status)
+					return m, m.queueAction(readToggle(entry.Status), entry)
 				}
-			case keyMatches(msg, Keys.Save):
+			case keyMatches(msg, m.Keys.ToggleStar):
 				if len(m.Entries) > 0 {
-					entry := m.Entries[m.Cursor]
-					return m, m.saveEntry(entry.ID)
+					return m, m.toggleStar(m.Entries[m.Cursor])
+				}
+			case keyMatches(msg, m.Keys.Save):
+				if len(m.Entries) > 0 {
+					return m, m.saveEntry(m.Entries[m.Cursor])
 				}
-			case keyMatches(msg, Keys.OpenBrowser):
+			case keyMatches(msg, m.Keys.OpenBrowser):
 				if len(m.Entries) > 0 {
 					entry := m.Entries[m.Cursor]
 					return m, openUrl(entry.URL)
@@ -195,36 +508,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case StateReading:
 			// Handle component specific keys first if needed, or global keys above
 			switch {
-			case keyMatches(msg, Keys.ToggleRead):
+			case keyMatches(msg, m.Keys.ToggleRead):
 				if m.Selected != nil {
-					entry := m.Selected
-					// We need to find it in the list to update it too?
-					// Simplification: just update selected and send request.
-					if entry.Status == miniflux.ReadStatusRead {
-						entry.Status = miniflux.ReadStatusUnread
-					} else {
-						entry.Status = miniflux.ReadStatusRead
-					}
-					// Also update in list if possible
-					if m.Cursor >= 0 && m.Cursor < len(m.Entries) && m.Entries[m.Cursor].ID == entry.ID {
-						m.Entries[m.Cursor].Status = entry.Status
-					}
-
-					return m, m.toggleReadStatus(entry.ID, entry.Status)
+					return m, m.queueAction(readToggle(m.Selected.Status), *m.Selected)
+				}
+			case keyMatches(msg, m.Keys.ToggleStar):
+				if m.Selected != nil {
+					return m, m.toggleStar(*m.Selected)
 				}
-			case keyMatches(msg, Keys.Save):
+			case keyMatches(msg, m.Keys.Save):
 				if m.Selected != nil {
-					return m, m.saveEntry(m.Selected.ID)
+					return m, m.saveEntry(*m.Selected)
 				}
-			case keyMatches(msg, Keys.OpenBrowser):
+			case keyMatches(msg, m.Keys.OpenBrowser):
 				if m.Selected != nil {
 					return m, openUrl(m.Selected.URL)
 				}
+			case keyMatches(msg, m.Keys.FetchOriginal):
+				if m.Selected != nil {
+					return m, m.fetchContent(m.Selected.ID)
+				}
+			case keyMatches(msg, m.Keys.NextMatch):
+				m.jumpToMatch(1)
+				return m, nil
+			case keyMatches(msg, m.Keys.PrevMatch):
+				m.jumpToMatch(-1)
+				return m, nil
 			}
 
 			// Forward other keys to viewport (scrolling)
 			m.Viewport, cmd = m.Viewport.Update(msg)
 			cmds = append(cmds, cmd)
+		case StateFeeds:
+			return m.updateFeeds(msg)
+		case StateProfiles:
+			return m.updateProfiles(msg)
 		}
 
 	case tea.WindowSizeMsg:
@@ -233,90 +551,371 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if m.State == StateReading && m.Selected != nil {
 			// Re-render content with new width
-			m.Viewport.SetContent(renderEntryContent(m.Selected, msg.Width))
+			m.renderReader()
 		}
 
 	case EntriesMsg:
-		m.Entries = msg
-		m.State = StateList
-		m.Cursor = 0
+		if msg.View != m.CurrentView || msg.Filter != m.Filter || msg.Search != m.Search {
+			// Stale page from before the view, filter or search changed
+			break
+		}
+		m.Total = msg.Total
+		m.NextOffset = msg.Offset + len(msg.Entries)
+		m.LoadingMore = false
+		if msg.Cached && msg.Offset == 0 {
+			cmds = append(cmds, m.setStatus("Server unreachable - searching cached entries only", true))
+		}
+		if msg.Reload {
+			m.ready = true
+			m.Loaded = true
+			if m.State == StateLoading {
+				m.State = StateList
+			}
+			m.Cursor = cursorAfterReload(m.Entries, msg.Entries, m.Cursor)
+			m.Entries = msg.Entries
+			break
+		}
+		if msg.Offset == 0 {
+			m.ready = true
+			m.Entries = msg.Entries
+			m.Loaded = true
+			m.State = StateList
+			m.Cursor = 0
+			break
+		}
+		if len(msg.Entries) == 0 {
+			// The server ran out early, e.g. entries were read elsewhere
+			m.Total = len(m.Entries)
+		}
+		m.appendEntries(msg.Entries)
+
+	case FeedTreeMsg:
+		m.FeedTree = buildFeedTree(msg.Categories, msg.Feeds, msg.Counters)
+		m.Unread = m.FeedTree[0].Unread
+		m.State = StateFeeds
+		m.FeedCursor = 0
+		for i, node := range m.FeedTree {
+			if node.matches(m.Filter) {
+				m.FeedCursor = i
+				break
+			}
+		}
 
 	case ErrorMsg:
-		m.Err = msg
-		m.State = StateError
+		m.LoadingMore = false
+		m.FetchingContent = false
+		if m.Form != nil {
+			m.Form.Busy = false
+		}
+		if !m.ready {
+			m.Err = msg
+			m.State = StateError
+			break
+		}
+		if m.State == StateLoading {
+			m.State = StateList
+		}
+		cmds = append(cmds, m.setStatus(m.errorText(msg), true))
+
+	case UndoneMsg:
+		cmds = append(cmds, m.undone(msg.EntryIDs))
+
+	case DiscoveredMsg:
+		cmds = append(cmds, m.chooseSubscription(msg))
+
+	case FeedLoadedMsg:
+		cmds = append(cmds, m.openEditFeed(msg.Feed))
+
+	case FeedsChangedMsg:
+		cmds = append(cmds, m.feedsChanged(msg))
+
+	case SyncedMsg:
+		cmds = append(cmds, m.synced(msg))
+
+	case ReplayedMsg:
+		cmds = append(cmds, m.replayed(msg))
+
+	case outboxRetryMsg:
+		if m.Queued > 0 {
+			cmds = append(cmds, m.flushOutbox())
+		}
+
+	case MarkedAllReadMsg:
+		cmds = append(cmds, m.markedAllRead(msg.EntryIDs))
+
+	case ProfileResolvedMsg:
+		cmds = append(cmds, m.profileResolved(msg))
+
+	case MarkFailedMsg:
+		cmds = append(cmds, m.markFailed(msg))
+
+	case undoExpiredMsg:
+		if m.Undo != nil && m.Undo.seq == msg.seq {
+			m.Undo = nil
+		}
+
+	case UnreadCountMsg:
+		m.Unread = int(msg)
+
+	case clearStatusMsg:
+		if msg.seq == m.statusSeq {
+			m.StatusText = ""
+			m.StatusIsError = false
+		}
+
+	case spinner.TickMsg:
+		m.Spinner, cmd = m.Spinner.Update(msg)
+		cmds = append(cmds, cmd)
 
 	case EntryContentMsg:
-		// Handle original content fetching if we implement that feature fully
-		// For now we rely on the feed content
+		m.FetchingContent = false
+		if m.Store != nil {
+			// The cache is best effort; the content is still shown
+			_ = m.Store.SetOriginalContent(msg.EntryID, msg.Content)
+		}
+		for i := range m.Entries {
+			if m.Entries[i].ID == msg.EntryID {
+				m.Entries[i].OriginalContent = msg.Content
+			}
+		}
+		// Selected may point into an older copy of Entries after a page load
+		if m.Selected != nil && m.Selected.ID == msg.EntryID {
+			m.Selected.OriginalContent = msg.Content
+			if m.State == StateReading {
+				m.renderReader()
+			}
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m Model) View() string {
+	if m.Confirm != nil && m.State != StateError {
+		body := m.Viewport.View()
+		switch m.State {
+		case StateList:
+			body = m.viewList()
+		case StateFeeds:
+			body = m.viewFeeds()
+		case StateProfiles:
+			body = m.viewProfiles()
+		}
+		return m.padToViewport(body) + "\n" + m.viewConfirm()
+	}
+
+	if m.ShowHelp {
+		return m.withStatusBar(m.viewHelp())
+	}
+
 	switch m.State {
 	case StateLoading:
-		return "Loading..."
+		return m.withStatusBar("Loading...")
 	case StateError:
-		return fmt.Sprintf("Error: %v", m.Err)
+		return m.Styles.ErrorMessage.Render("Error: "+m.errorText(m.Err)) +
+			"\n\n" + m.Styles.Dim.Render(fmt.Sprintf("Press %s to retry or %s to quit.", m.Keys.Refresh.Help().Key, m.Keys.Quit.Help().Key))
 	case StateReading:
-		return m.Viewport.View()
+		return m.withStatusBar(m.Viewport.View())
 	case StateList:
-		return m.viewList()
+		return m.withStatusBar(m.viewList())
+	case StateSearch:
+		// The prompt takes the place of the status bar while typing
+		return m.padToViewport(m.viewList()) + "\n" + m.SearchInput.View()
+	case StateFeeds:
+		return m.withStatusBar(m.viewFeeds())
+	case StateProfiles:
+		return m.withStatusBar(m.viewProfiles())
+	case StateForm:
+		return m.withStatusBar(m.viewForm())
 	}
 	return ""
 }
 
-func (m Model) viewList() string {
-	var s strings.Builder
-	s.WriteString(StyleTitle.Render("Miniflux Feeds") + "\n\n")
+// errorText turns API failures into a message the user can act on.
+func (m Model) errorText(err error) string {
+	var apiErr *miniflux.APIError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case miniflux.IsUnauthorized(err):
+		return "credentials rejected - " + m.credentialHint()
+	case miniflux.IsNotFound(err):
+		return "not found on the server; it may have been deleted"
+	case miniflux.IsRateLimited(err):
+		return "rate limited by the server - wait a moment and retry"
+	case miniflux.IsServerError(err) && errors.As(err, &apiErr):
+		return fmt.Sprintf("server error (status %d): %s", apiErr.StatusCode, apiErr.Message)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "the server took too long to respond - check server_url or request_timeout"
+	case errors.Is(err, miniflux.ErrPinMismatch):
+		return "server certificate does not match pinned_sha256"
+	case errors.As(err, &certErr):
+		return "server certificate not trusted - check ca_file: " + certErr.Err.Error()
+	}
+	return err.Error()
+}
 
-	// Simple windowing for long lists
-	start := 0
-	end := len(m.Entries)
-	height := 20 // Arbitrary default, usually bound to WindowSize
-	if m.Viewport.Height > 0 {
-		height = m.Viewport.Height - 4 // Title + padding
+// credentialHint says where the rejected credentials came from.
+func (m Model) credentialHint() string {
+	source := m.Config.CredentialSource()
+	if source == config.EnvAPIKey {
+		return "check " + source
 	}
+	path, err := config.GetConfigFilepath()
+	if err != nil {
+		path = "config.toml"
+	}
+	if m.Config.ActiveProfile != "" {
+		return fmt.Sprintf("check %s under [profiles.%s] in %s", source, m.Config.ActiveProfile, path)
+	}
+	return fmt.Sprintf("check %s at the top of %s", source, path)
+}
 
-	if m.Cursor >= height {
-		start = m.Cursor - height + 1
+func (m Model) viewList() string {
+	var s strings.Builder
+	title := "Miniflux Feeds"
+	if m.Filter.Title != "" {
+		title += " - " + m.Filter.Title
 	}
-	if end > start+height {
-		end = start + height
+	if m.Search != "" {
+		title = fmt.Sprintf("Search: %q", m.Search)
+	}
+	s.WriteString(m.Styles.Title.Render(title))
+	s.WriteString(m.Styles.Dim.Render(fmt.Sprintf(" %d of %d", len(m.Entries), m.Total)) + "\n")
+	if m.Search != "" {
+		s.WriteString(m.Styles.Dim.Render(" esc to return") + "\n\n")
+	} else {
+		s.WriteString(m.viewTabs() + "\n\n")
 	}
 
+	pattern := m.searchPattern()
+
+	start, end := m.listWindow(m.Cursor, len(m.Entries))
 	for i := start; i < end; i++ {
 		entry := m.Entries[i]
 		cursor := " "
-		style := StyleBase
+		style := m.Styles.Base
 
 		if m.Cursor == i {
 			cursor = ">"
-			style = StyleSelected
+			style = m.Styles.Selected
 		}
 
 		if entry.Status == miniflux.ReadStatusUnread {
-			// style = style.Foreground(lipgloss.Color(m.Config.Theme.UnreadColor))
-			// Simpler handling of theme for now
 			if m.Cursor != i {
-				style = StyleStatusUnread
+				style = m.Styles.Unread
 			}
 		} else {
 			if m.Cursor != i {
-				style = StyleStatusRead
+				style = m.Styles.Read
 			}
 		}
 
-		line := fmt.Sprintf("%s %s", cursor, truncate(entry.Title, 80))
-		s.WriteString(style.Render(line) + "\n")
+		star := " "
+		if entry.Starred {
+			star = "*"
+		}
+		s.WriteString(style.Render(cursor+star) + highlight(truncate(entry.Title, 80), pattern, style, m.Styles.Match) + "\n")
+	}
+
+	if m.LoadingMore {
+		s.WriteString(m.Styles.Dim.Render("  Loading more...") + "\n")
 	}
 
 	return s.String()
 }
 
+// listWindow returns the range of rows to draw so that cursor stays visible.
+func (m Model) listWindow(cursor, total int) (int, int) {
+	// Simple windowing for long lists
+	start := 0
+	end := total
+	height := 20 // Arbitrary default, usually bound to WindowSize
+	if m.Viewport.Height > 0 {
+		height = m.Viewport.Height - 5 // Title + tabs + padding
+	}
+
+	if cursor >= height {
+		start = cursor - height + 1
+	}
+	if end > start+height {
+		end = start + height
+	}
+	return start, end
+}
+
+// renderReader renders the selected entry into the viewport, highlighting
+// search matches when the entry was opened from search results.
+func (m *Model) renderReader() {
+	content, matchLines := m.styleContent(renderEntryContent(m.Selected, m.Viewport.Width), m.searchPattern())
+	m.Viewport.SetContent(content)
+	m.MatchLines = matchLines
+	m.MatchIndex = -1
+}
+
+// Markers placed around <pre> blocks before conversion so styleContent
+// can tell code lines apart once the text has been wrapped.
+const (
+	codeStart = "\uE000"
+	codeEnd   = "\uE001"
+)
+
+var (
+	preOpen      = regexp.MustCompile(`(?i)<pre[^>]*>`)
+	preClose     = regexp.MustCompile(`(?i)</pre>`)
+	footnoteLine = regexp.MustCompile(`^\[\d+\] https?://`)
+)
+
+// styleContent applies theme styles line by line to rendered reader text
+// and highlights matches of re. It returns the styled text and the
+// indexes of lines containing a match.
+func (m Model) styleContent(content string, re *regexp.Regexp) (string, []int) {
+	var out []string
+	var matchLines []int
+	inCode := false
+	for i, line := range strings.Split(content, "\n") {
+		start, end := strings.Contains(line, codeStart), strings.Contains(line, codeEnd)
+		if start || end {
+			line = strings.NewReplacer(codeStart, "", codeEnd, "").Replace(line)
+		}
+		if start {
+			inCode = true
+		}
+
+		style := m.Styles.Base
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inCode:
+			style = m.Styles.Code
+		case i == 0:
+			style = m.Styles.Title.UnsetPadding()
+		case strings.HasPrefix(trimmed, ">"):
+			style = m.Styles.Quote
+		case footnoteLine.MatchString(trimmed):
+			style = m.Styles.Link
+		}
+		if end {
+			inCode = false
+		}
+		if (start || end) && trimmed == "" {
+			continue
+		}
+
+		if re != nil && re.MatchString(line) {
+			matchLines = append(matchLines, len(out))
+		}
+		out = append(out, highlight(line, re, style, m.Styles.Match))
+	}
+	return strings.Join(out, "\n"), matchLines
+}
+
 func renderEntryContent(entry *miniflux.FeedEntry, width int) string {
 	content := entry.Content
+	if entry.OriginalContent != "" {
+		content = entry.OriginalContent
+	}
+	content = preOpen.ReplaceAllString(content, "${0}"+codeStart+"\n")
+	content = preClose.ReplaceAllString(content, "\n"+codeEnd+"${0}")
 	text, err := html2text.FromString(content, html2text.Options{PrettyTables: true})
 	if err == nil {
 		content = text
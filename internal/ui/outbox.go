@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/miniflux"
+	"github.com/slatkin/goflux/pkg/store"
+)
+
+// outboxRetry is how long queued actions wait before being sent again
+// after the server could not be reached.
+const outboxRetry = 30 * time.Second
+
+type outboxRetryMsg struct{}
+
+// newOutbox uses the cache's durable outbox when there is a cache.
+func newOutbox(s *store.Store) *store.Outbox {
+	if s != nil {
+		return s.Outbox()
+	}
+	return store.NewOutbox()
+}
+
+// queueAction applies an entry action locally right away and queues it
+// for the server.
+func (m *Model) queueAction(kind store.ActionKind, entry miniflux.FeedEntry) tea.Cmd {
+	m.applyAction(kind, entry.ID, false)
+	if err := m.Outbox.Add(kind, entry.ID); err != nil {
+		m.applyAction(kind, entry.ID, true)
+		return m.setStatus("Could not queue change: "+err.Error(), true)
+	}
+	m.countQueued()
+	return m.flushOutbox()
+}
+
+// applyAction makes the local change for an action, or reverts it.
+func (m *Model) applyAction(kind store.ActionKind, entryID int, revert bool) {
+	switch kind {
+	case store.ActionRead, store.ActionUnread:
+		status := miniflux.ReadStatusRead
+		if (kind == store.ActionUnread) != revert {
+			status = miniflux.ReadStatusUnread
+		}
+		m.setLocalStatus(entryID, status)
+		m.storeStatus([]int{entryID}, status)
+	case store.ActionStar, store.ActionUnstar:
+		m.setLocalStarred(entryID, (kind == store.ActionStar) != revert)
+	}
+}
+
+// setLocalStarred sets an entry's bookmark in the list, reader and cache.
+func (m *Model) setLocalStarred(entryID int, starred bool) {
+	if m.Selected != nil && m.Selected.ID == entryID {
+		m.Selected.Starred = starred
+	}
+	for i := range m.Entries {
+		if m.Entries[i].ID == entryID {
+			m.Entries[i].Starred = starred
+		}
+	}
+	if m.Store != nil {
+		_ = m.Store.SetStarred(entryID, starred)
+	}
+}
+
+// flushOutbox replays queued actions in the background.
+func (m *Model) flushOutbox() tea.Cmd {
+	if m.Flushing {
+		m.flushAgain = true
+		return nil
+	}
+	m.Flushing = true
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.cancelFlush = cancel
+	outbox, client, gen := m.Outbox, m.Client, m.profileGen
+	return func() tea.Msg {
+		defer cancel()
+		result, err := outbox.Replay(ctx, client)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return ReplayedMsg{Result: result, Err: err, gen: gen}
+	}
+}
+
+func (m *Model) replayed(msg ReplayedMsg) tea.Cmd {
+	if msg.gen != m.profileGen {
+		return nil
+	}
+	m.Flushing = false
+	m.countQueued()
+
+	var cmds []tea.Cmd
+	for _, failed := range msg.Result.Failed {
+		m.applyAction(failed.Kind, failed.EntryID, true)
+	}
+	if n := len(msg.Result.Failed); n > 0 {
+		first := msg.Result.Failed[0]
+		text := fmt.Sprintf("Could not %s %q: %s - reverted", actionVerb(first.Kind), m.entryTitle(first.EntryID), m.errorText(first.Err))
+		if n > 1 {
+			text += fmt.Sprintf(" (and %d more)", n-1)
+		}
+		cmds = append(cmds, m.setStatus(text, true), m.fetchUnreadCount)
+	}
+
+	switch {
+	case m.flushAgain:
+		m.flushAgain = false
+		cmds = append(cmds, m.flushOutbox())
+	case msg.Err != nil:
+		cmds = append(cmds, tea.Tick(outboxRetry, func(time.Time) tea.Msg {
+			return outboxRetryMsg{}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// confirmLosingQueued runs next, asking first if queued changes would be
+// lost because the outbox only lives in memory without a cache.
+func (m *Model) confirmLosingQueued(action string, next func(m *Model) tea.Cmd) tea.Cmd {
+	if m.Queued == 0 || m.Store != nil {
+		return next(m)
+	}
+	m.confirm(fmt.Sprintf("Changes not sent to the server yet will be lost (%d queued). %s anyway?", m.Queued, action), next)
+	return nil
+}
+
+func (m *Model) countQueued() {
+	if pending, err := m.Outbox.Pending(); err == nil {
+		m.Queued = len(pending)
+	}
+}
+
+func actionVerb(kind store.ActionKind) string {
+	switch kind {
+	case store.ActionRead:
+		return "mark as read"
+	case store.ActionUnread:
+		return "mark as unread"
+	case store.ActionStar:
+		return "star"
+	case store.ActionUnstar:
+		return "remove the star from"
+	case store.ActionSave:
+		return "save"
+	}
+	return string(kind)
+}
+
+func (m Model) entryTitle(entryID int) string {
+	for _, entry := range m.Entries {
+		if entry.ID == entryID {
+			return entry.Title
+		}
+	}
+	if m.Store != nil {
+		if entry, ok, err := m.Store.Entry(entryID); err == nil && ok {
+			return entry.Title
+		}
+	}
+	return fmt.Sprintf("entry %d", entryID)
+}
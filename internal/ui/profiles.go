@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/internal/client"
+)
+
+// profileChoices lists the profiles to offer, with "" standing for the
+// top-level server settings.
+func (m Model) profileChoices() []string {
+	return append([]string{""}, m.Config.ProfileNames()...)
+}
+
+func profileLabel(name string) string {
+	if name == "" {
+		return "(default)"
+	}
+	return name
+}
+
+func (m *Model) openProfiles() tea.Cmd {
+	choices := m.profileChoices()
+	if len(choices) == 1 {
+		return m.setStatus("No [profiles] configured", false)
+	}
+	m.State = StateProfiles
+	m.ProfileCursor = 0
+	for i, name := range choices {
+		if name == m.Config.ActiveProfile {
+			m.ProfileCursor = i
+		}
+	}
+	return nil
+}
+
+func (m Model) updateProfiles(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choices := m.profileChoices()
+	switch {
+	case keyMatches(msg, m.Keys.Up):
+		if m.ProfileCursor > 0 {
+			m.ProfileCursor--
+		}
+	case keyMatches(msg, m.Keys.Down):
+		if m.ProfileCursor < len(choices)-1 {
+			m.ProfileCursor++
+		}
+	case keyMatches(msg, m.Keys.Enter):
+		return m, m.switchProfile(choices[m.ProfileCursor])
+	}
+	return m, nil
+}
+
+func (m *Model) switchProfile(name string) tea.Cmd {
+	if name == m.Config.ActiveProfile {
+		m.State = StateList
+		return nil
+	}
+	return m.confirmLosingQueued("Switch profiles", func(m *Model) tea.Cmd {
+		return m.resolveProfile(name)
+	})
+}
+
+// resolveProfile resolves the credentials of another profile outside
+// Update. A credential command gets the terminal to itself, since tools
+// like `pass` may ask for a passphrase through a pinentry on it.
+func (m *Model) resolveProfile(name string) tea.Cmd {
+	base := m.Config
+	resolve := func() ProfileResolvedMsg {
+		cfg, err := base.WithProfile(name)
+		return ProfileResolvedMsg{Name: name, Config: cfg, Err: err}
+	}
+	if !base.RunsCommand(name) {
+		return func() tea.Msg { return resolve() }
+	}
+
+	var msg ProfileResolvedMsg
+	return tea.Exec(&credentialCommand{
+		profile: profileLabel(name),
+		run:     func() { msg = resolve() },
+	}, func(error) tea.Msg { return msg })
+}
+
+// credentialCommand runs a profile's credential lookup while the TUI has
+// released the terminal.
+type credentialCommand struct {
+	profile string
+	run     func()
+	stdout  io.Writer
+}
+
+func (c *credentialCommand) Run() error {
+	if c.stdout != nil {
+		fmt.Fprintf(c.stdout, "goflux: reading credentials for profile %s...\n", c.profile)
+	}
+	c.run()
+	return nil
+}
+
+func (c *credentialCommand) SetStdin(io.Reader)    {}
+func (c *credentialCommand) SetStdout(w io.Writer) { c.stdout = w }
+func (c *credentialCommand) SetStderr(io.Writer)   {}
+
+// profileResolved reconnects to the new profile and reloads everything
+// shown from the old one.
+func (m *Model) profileResolved(msg ProfileResolvedMsg) tea.Cmd {
+	if msg.Err != nil {
+		m.State = StateList
+		return m.setStatus(msg.Err.Error(), true)
+	}
+	cfg, name := msg.Config, msg.Name
+
+	m.cancelLoads()
+	m.cancelContentFetch()
+	m.Config = cfg
+	m.Client = client.New(cfg)
+	m.closeStore()
+	status := "Switched to profile " + profileLabel(name)
+	s, err := openStore(cfg)
+	if err != nil {
+		status = "offline cache disabled: " + err.Error()
+	}
+	m.Store = s
+	m.Outbox = newOutbox(s)
+	m.Flushing, m.flushAgain = false, false
+	m.profileGen++
+	m.countQueued()
+	m.entryList = entryList{}
+	m.SavedLists = [viewCount]entryList{}
+	m.Filter = EntryFilter{}
+	m.Search = ""
+	m.Selected = nil
+	m.FeedTree = nil
+	m.Undo = nil
+	m.Unread = 0
+	m.Syncing = false
+	m.State = StateLoading
+
+	return tea.Batch(m.load(), m.setStatus(status, err != nil))
+}
+
+func (m Model) viewProfiles() string {
+	var s strings.Builder
+	s.WriteString(m.Styles.Title.Render("Profiles") + "\n\n")
+
+	for i, name := range m.profileChoices() {
+		cursor := " "
+		style := m.Styles.Base
+		if m.ProfileCursor == i {
+			cursor = ">"
+			style = m.Styles.Selected
+		}
+		label := profileLabel(name)
+		if name == m.Config.ActiveProfile {
+			label += " *"
+		}
+		server := m.Config.Profiles[name].ServerUrl
+		if name == "" {
+			server = "top-level server_url"
+		}
+		s.WriteString(style.Render(fmt.Sprintf("%s %s", cursor, label)) + m.Styles.Dim.Render("  "+server) + "\n")
+	}
+
+	return s.String()
+}
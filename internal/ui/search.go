@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "search all entries"
+	ti.CharLimit = 200
+	return ti
+}
+
+func (m *Model) openSearchPrompt() tea.Cmd {
+	m.State = StateSearch
+	m.SearchInput.SetValue("")
+	return m.SearchInput.Focus()
+}
+
+func (m Model) updateSearchPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.SearchInput.Blur()
+		query := strings.TrimSpace(m.SearchInput.Value())
+		if query == "" {
+			m.State = StateList
+			return m, nil
+		}
+		return m, m.startSearch(query)
+	case tea.KeyEsc:
+		m.SearchInput.Blur()
+		m.State = StateList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.SearchInput, cmd = m.SearchInput.Update(msg)
+	return m, cmd
+}
+
+// startSearch replaces the list with server-side search results. The
+// current view's list is stashed and restored by exitSearch.
+func (m *Model) startSearch(query string) tea.Cmd {
+	if m.Search == "" {
+		m.SavedLists[m.CurrentView] = m.entryList
+	}
+	m.Search = query
+	m.entryList = entryList{}
+	m.State = StateLoading
+	return m.fetchEntries(0)
+}
+
+// exitSearch restores the list the search replaced. A sync may have
+// discarded it meanwhile, in which case it is loaded again.
+func (m *Model) exitSearch() tea.Cmd {
+	m.cancelLoads()
+	m.Search = ""
+	m.entryList = m.SavedLists[m.CurrentView]
+	if m.Loaded {
+		return nil
+	}
+	m.State = StateLoading
+	return m.fetchEntries(0)
+}
+
+// searchPattern matches any of the words in the current search,
+// ignoring case. It returns nil when no search is active.
+func (m Model) searchPattern() *regexp.Regexp {
+	var terms []string
+	for _, term := range strings.Fields(m.Search) {
+		term = strings.Trim(term, `"'`)
+		if term != "" {
+			terms = append(terms, regexp.QuoteMeta(term))
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// highlight renders s in base, with every match of re rendered in match.
+func highlight(s string, re *regexp.Regexp, base, match lipgloss.Style) string {
+	if re == nil {
+		return base.Render(s)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if loc[0] > last {
+			b.WriteString(base.Render(s[last:loc[0]]))
+		}
+		b.WriteString(match.Render(s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(s) {
+		b.WriteString(base.Render(s[last:]))
+	}
+	return b.String()
+}
+
+// jumpToMatch scrolls the reader to the next (dir > 0) or previous match.
+func (m *Model) jumpToMatch(dir int) {
+	if len(m.MatchLines) == 0 {
+		return
+	}
+	switch {
+	case m.MatchIndex < 0 && dir > 0:
+		m.MatchIndex = 0
+	case m.MatchIndex < 0:
+		m.MatchIndex = len(m.MatchLines) - 1
+	default:
+		m.MatchIndex = (m.MatchIndex + dir + len(m.MatchLines)) % len(m.MatchLines)
+	}
+	m.Viewport.SetYOffset(m.MatchLines[m.MatchIndex])
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusTimeout is how long toasts and transient errors stay visible.
+const statusTimeout = 4 * time.Second
+
+type clearStatusMsg struct {
+	seq int
+}
+
+func newSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.MiniDot
+	return s
+}
+
+// setStatus shows a message in the status bar until it is replaced or
+// statusTimeout elapses.
+func (m *Model) setStatus(text string, isError bool) tea.Cmd {
+	return m.setStatusFor(text, isError, statusTimeout)
+}
+
+func (m *Model) setStatusFor(text string, isError bool, d time.Duration) tea.Cmd {
+	m.statusSeq++
+	m.StatusText = text
+	m.StatusIsError = isError
+	seq := m.statusSeq
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return clearStatusMsg{seq: seq}
+	})
+}
+
+func (m Model) busy() bool {
+	return m.State == StateLoading || m.LoadingMore || m.FetchingContent || m.Syncing || (m.Form != nil && m.Form.Busy)
+}
+
+func (m Model) viewName() string {
+	switch {
+	case m.State == StateFeeds:
+		return "Feeds"
+	case m.State == StateProfiles:
+		return "Profiles"
+	case m.State == StateForm:
+		return m.Form.Title
+	case m.Search != "":
+		return "Search"
+	}
+	return m.CurrentView.String()
+}
+
+func serverHost(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return serverURL
+	}
+	return u.Host
+}
+
+func (m Model) viewStatusBar() string {
+	left := " " + m.viewName()
+	if m.busy() {
+		left = " " + m.Spinner.View() + left
+	}
+
+	message := ""
+	messageStyle := m.Styles.StatusBar
+	if m.StatusText != "" {
+		message = " | " + m.StatusText
+		if m.StatusIsError {
+			messageStyle = m.Styles.StatusBarError
+		}
+	}
+
+	server := serverHost(m.Config.ServerUrl)
+	if m.Config.ActiveProfile != "" {
+		server = m.Config.ActiveProfile + " (" + server + ")"
+	}
+	right := fmt.Sprintf("%d unread | %s ", m.Unread, server)
+	if m.Queued > 0 {
+		right = fmt.Sprintf("%d queued | ", m.Queued) + right
+	}
+
+	gap := m.Viewport.Width - lipgloss.Width(left) - lipgloss.Width(message) - lipgloss.Width(right)
+	if gap < 1 {
+		gap = 1
+	}
+	// Each segment is rendered on its own so the bar background is not
+	// reset partway through the line.
+	return m.Styles.StatusBar.Render(left) +
+		messageStyle.Render(message) +
+		m.Styles.StatusBar.Render(strings.Repeat(" ", gap)+right)
+}
+
+// padToViewport pads body with blank lines to the viewport height so
+// whatever follows sits on the last line of the screen.
+func (m Model) padToViewport(body string) string {
+	body = strings.TrimSuffix(body, "\n")
+	if lines := strings.Count(body, "\n") + 1; lines < m.Viewport.Height {
+		body += strings.Repeat("\n", m.Viewport.Height-lines)
+	}
+	return body
+}
+
+func (m Model) withStatusBar(body string) string {
+	return m.padToViewport(body) + "\n" + m.viewStatusBar()
+}
@@ -1,37 +1,146 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/slatkin/goflux/pkg/config"
+)
+
+// palette is one built-in color scheme, as ANSI 256 indexes or hex.
+type palette struct {
+	Text, Dim, Title, Unread, Read   string
+	SelectedFg, SelectedBg           string
+	StatusBarFg, StatusBarBg         string
+	Link, Quote, Code, Error, Border string
+	MatchFg, MatchBg                 string
+}
 
 var (
-	// Colors
-	ColorPrimary   = lipgloss.Color("62") // Purpleish
-	ColorSecondary = lipgloss.Color("230")
-	ColorText      = lipgloss.Color("252")
-	ColorDim       = lipgloss.Color("240")
-	ColorError     = lipgloss.Color("196")
-	ColorSuccess   = lipgloss.Color("46")
-
-	// Styles
-	StyleBase = lipgloss.NewStyle().Foreground(ColorText)
-
-	StyleSelected = lipgloss.NewStyle().
-			Foreground(ColorSecondary).
-			Background(ColorPrimary).
-			Bold(true)
-
-	StyleTitle = lipgloss.NewStyle().
-			Foreground(ColorPrimary).
+	paletteDark = palette{
+		Text: "252", Dim: "240", Title: "62", Unread: "255", Read: "240",
+		SelectedFg: "230", SelectedBg: "62",
+		StatusBarFg: "252", StatusBarBg: "236",
+		Link: "39", Quote: "109", Code: "180", Error: "196", Border: "62",
+		MatchFg: "0", MatchBg: "220",
+	}
+	paletteLight = palette{
+		Text: "235", Dim: "245", Title: "55", Unread: "232", Read: "245",
+		SelectedFg: "255", SelectedBg: "62",
+		StatusBarFg: "235", StatusBarBg: "253",
+		Link: "25", Quote: "66", Code: "130", Error: "160", Border: "62",
+		MatchFg: "0", MatchBg: "221",
+	}
+)
+
+type Styles struct {
+	Base           lipgloss.Style
+	Selected       lipgloss.Style
+	Title          lipgloss.Style
+	Unread         lipgloss.Style
+	Read           lipgloss.Style
+	Dim            lipgloss.Style
+	Tab            lipgloss.Style
+	TabActive      lipgloss.Style
+	Match          lipgloss.Style
+	StatusBar      lipgloss.Style
+	StatusBarError lipgloss.Style
+	HelpBox        lipgloss.Style
+	ErrorMessage   lipgloss.Style
+	Link           lipgloss.Style
+	Quote          lipgloss.Style
+	Code           lipgloss.Style
+}
+
+// NewStyles builds the UI styles from a theme. Colors the theme leaves
+// empty come from its preset; the "auto" preset picks between the light
+// and dark palettes based on the terminal background.
+func NewStyles(theme config.ThemeConfig) Styles {
+	color := func(override, light, dark string) lipgloss.TerminalColor {
+		if override != "" {
+			c, err := config.NormalizeColor(override)
+			if err != nil || c == "" {
+				return lipgloss.NoColor{}
+			}
+			return lipgloss.Color(c)
+		}
+		switch theme.Preset {
+		case "light":
+			return lipgloss.Color(light)
+		case "dark":
+			return lipgloss.Color(dark)
+		}
+		return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+	}
+	l, d := paletteLight, paletteDark
+
+	text := color(theme.TextColor, l.Text, d.Text)
+	dim := color(theme.DimColor, l.Dim, d.Dim)
+	title := color(theme.TitleColor, l.Title, d.Title)
+	selectedFg := color(theme.SelectedForeground, l.SelectedFg, d.SelectedFg)
+	selectedBg := color(theme.SelectedBackground, l.SelectedBg, d.SelectedBg)
+	statusBar := lipgloss.NewStyle().
+		Foreground(color(theme.StatusBarForeground, l.StatusBarFg, d.StatusBarFg)).
+		Background(color(theme.StatusBarBackground, l.StatusBarBg, d.StatusBarBg))
+	errColor := color(theme.ErrorColor, l.Error, d.Error)
+
+	return Styles{
+		Base: lipgloss.NewStyle().Foreground(text),
+
+		Selected: lipgloss.NewStyle().
+			Foreground(selectedFg).
+			Background(selectedBg).
+			Bold(true),
+
+		Title: lipgloss.NewStyle().
+			Foreground(title).
 			Bold(true).
-			Padding(0, 1)
+			Padding(0, 1),
 
-	StyleStatusUnread = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")). // Bright white
-				Bold(true)
+		Unread: lipgloss.NewStyle().
+			Foreground(color(theme.UnreadColor, l.Unread, d.Unread)).
+			Bold(true),
 
-	StyleStatusRead = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Read: lipgloss.NewStyle().
+			Foreground(color(theme.ReadColor, l.Read, d.Read)),
 
-	StyleErrorMessage = lipgloss.NewStyle().
-				Foreground(ColorError).
-				Bold(true)
-)
+		Dim: lipgloss.NewStyle().Foreground(dim),
+
+		Tab: lipgloss.NewStyle().
+			Foreground(dim).
+			Padding(0, 1),
+
+		TabActive: lipgloss.NewStyle().
+			Foreground(selectedFg).
+			Background(selectedBg).
+			Padding(0, 1),
+
+		Match: lipgloss.NewStyle().
+			Foreground(color(theme.MatchForeground, l.MatchFg, d.MatchFg)).
+			Background(color(theme.MatchBackground, l.MatchBg, d.MatchBg)),
+
+		StatusBar: statusBar,
+
+		StatusBarError: statusBar.
+			Foreground(errColor).
+			Bold(true),
+
+		HelpBox: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(color(theme.BorderColor, l.Border, d.Border)).
+			Padding(1, 2),
+
+		ErrorMessage: lipgloss.NewStyle().
+			Foreground(errColor).
+			Bold(true),
+
+		Link: lipgloss.NewStyle().
+			Foreground(color(theme.LinkColor, l.Link, d.Link)).
+			Underline(true),
+
+		Quote: lipgloss.NewStyle().
+			Foreground(color(theme.QuoteColor, l.Quote, d.Quote)).
+			Italic(true),
+
+		Code: lipgloss.NewStyle().
+			Foreground(color(theme.CodeColor, l.Code, d.Code)),
+	}
+}
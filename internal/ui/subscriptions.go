@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// categoryChoices lists the categories in the feed tree, for the
+// category field of a feed form.
+func (m Model) categoryChoices() (titles []string, ids []int) {
+	for _, node := range m.FeedTree {
+		if node.Kind == feedNodeCategory {
+			titles = append(titles, node.Title)
+			ids = append(ids, node.ID)
+		}
+	}
+	return titles, ids
+}
+
+// cursorCategory is the category under the feed tree cursor, or the one
+// the feed under the cursor belongs to.
+func (m Model) cursorCategory() int {
+	for i := min(m.FeedCursor, len(m.FeedTree)-1); i >= 0; i-- {
+		if m.FeedTree[i].Kind == feedNodeCategory {
+			return m.FeedTree[i].ID
+		}
+	}
+	return 0
+}
+
+func indexOf(ids []int, id int) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return 0
+}
+
+// openSubscribe asks for a website or feed URL to subscribe to. Every
+// feed needs a category, so there must be one first.
+func (m *Model) openSubscribe() tea.Cmd {
+	if _, ids := m.categoryChoices(); len(ids) == 0 {
+		return m.noCategories()
+	}
+	return m.openForm(&form{
+		Title:  "Subscribe",
+		Fields: []formField{textField("Website or feed URL", "")},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			url := f.Fields[0].Value()
+			if url == "" {
+				f.Busy = false
+				return nil
+			}
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				subs, err := client.Discover(ctx, url)
+				if err != nil {
+					return errorMsg(err)
+				}
+				return DiscoveredMsg{URL: url, Subscriptions: subs}
+			}
+		},
+	})
+}
+
+// chooseSubscription lets the user pick one of the discovered feeds and
+// its category.
+func (m *Model) chooseSubscription(msg DiscoveredMsg) tea.Cmd {
+	if m.Form == nil {
+		// Cancelled while discovering
+		return nil
+	}
+	if len(msg.Subscriptions) == 0 {
+		m.Form.Busy = false
+		return m.setStatus("No feeds found at "+msg.URL, true)
+	}
+
+	feeds := make([]string, len(msg.Subscriptions))
+	for i, sub := range msg.Subscriptions {
+		feeds[i] = fmt.Sprintf("%s (%s)", sub.Title, sub.URL)
+	}
+	titles, ids := m.categoryChoices()
+	if len(ids) == 0 {
+		m.closeForm()
+		return m.noCategories()
+	}
+	return m.openForm(&form{
+		Title: "Subscribe",
+		Fields: []formField{
+			choiceField("Feed", feeds, 0),
+			choiceField("Category", titles, indexOf(ids, m.cursorCategory())),
+			toggleField("Fetch original content", false),
+			textField("Scraper rules", ""),
+		},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			sub := msg.Subscriptions[f.Fields[0].Choice]
+			req := miniflux.FeedCreationRequest{
+				FeedURL:      sub.URL,
+				CategoryID:   ids[f.Fields[1].Choice],
+				Crawler:      f.Fields[2].On,
+				ScraperRules: f.Fields[3].Value(),
+			}
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				if _, err := client.CreateFeed(ctx, req); err != nil {
+					return errorMsg(err)
+				}
+				return FeedsChangedMsg{Text: "Subscribed to " + sub.Title}
+			}
+		},
+	})
+}
+
+func (m *Model) noCategories() tea.Cmd {
+	return m.setStatus(fmt.Sprintf("Create a category first (%s)", m.Keys.AddCategory.Help().Key), true)
+}
+
+// loadFeedForEdit fetches the full settings of the feed under the cursor.
+func (m Model) loadFeedForEdit() tea.Cmd {
+	if len(m.FeedTree) == 0 || m.FeedTree[m.FeedCursor].Kind != feedNodeFeed {
+		return nil
+	}
+	feedID, client, ctx := m.FeedTree[m.FeedCursor].ID, m.Client, m.ctx
+	return func() tea.Msg {
+		feed, err := client.GetFeed(ctx, feedID)
+		if err != nil {
+			return errorMsg(err)
+		}
+		return FeedLoadedMsg{Feed: feed}
+	}
+}
+
+func (m *Model) openEditFeed(feed miniflux.Feed) tea.Cmd {
+	titles, ids := m.categoryChoices()
+	return m.openForm(&form{
+		Title: "Edit " + feed.Title,
+		Fields: []formField{
+			textField("Title", feed.Title),
+			choiceField("Category", titles, indexOf(ids, feed.Category.ID)),
+			toggleField("Fetch original content", feed.Crawler),
+			textField("Scraper rules", feed.ScraperRules),
+			textField("Rewrite rules", feed.RewriteRules),
+		},
+		OnSubmit: func(m *Model, f *form) tea.Cmd {
+			title := f.Fields[0].Value()
+			crawler := f.Fields[2].On
+			scraper, rewrite := f.Fields[3].Value(), f.Fields[4].Value()
+			req := miniflux.FeedModificationRequest{
+				Crawler:      &crawler,
+				ScraperRules: &scraper,
+				RewriteRules: &rewrite,
+			}
+			if title != "" {
+				req.Title = &title
+			}
+			if len(ids) > 0 {
+				req.CategoryID = &ids[f.Fields[1].Choice]
+			}
+			client, ctx := m.Client, m.ctx
+			return func() tea.Msg {
+				if _, err := client.UpdateFeed(ctx, feed.ID, req); err != nil {
+					return errorMsg(err)
+				}
+				return FeedsChangedMsg{Text: "Updated " + title}
+			}
+		},
+	})
+}
+
+// promptUnsubscribe confirms before deleting the feed under the cursor.
+func (m *Model) promptUnsubscribe() {
+	if len(m.FeedTree) == 0 || m.FeedTree[m.FeedCursor].Kind != feedNodeFeed {
+		return
+	}
+	node := m.FeedTree[m.FeedCursor]
+	m.confirm(fmt.Sprintf("Unsubscribe from %q?", node.Title), func(m *Model) tea.Cmd {
+		client, ctx := m.Client, m.ctx
+		return func() tea.Msg {
+			if err := client.DeleteFeed(ctx, node.ID); err != nil {
+				return errorMsg(err)
+			}
+			return FeedsChangedMsg{Text: "Unsubscribed from " + node.Title, FeedID: node.ID, Deleted: true}
+		}
+	})
+}
+
+// feedsChanged closes the form and reloads the feed tree and entries
+// after a subscription or category was added, edited or removed, since
+// entries may now belong to another category.
+func (m *Model) feedsChanged(msg FeedsChangedMsg) tea.Cmd {
+	m.closeForm()
+	m.FeedSelection = nil
+	if msg.Category.ID != 0 && msg.Category.ID == m.Filter.CategoryID {
+		if msg.Deleted {
+			m.Filter = EntryFilter{}
+		} else {
+			m.Filter.Title = msg.Category.Title
+		}
+	}
+	if msg.Deleted && msg.FeedID != 0 && msg.FeedID == m.Filter.FeedID {
+		m.Filter = EntryFilter{}
+	}
+
+	status := m.setStatus(msg.Text, false)
+	if m.Store != nil {
+		// The sync refreshes the cached feeds, then the tree and entries
+		return tea.Batch(m.refresh(), status)
+	}
+	m.SavedLists = [viewCount]entryList{}
+	return tea.Batch(m.fetchFeedTree, m.reloadEntries(), status)
+}
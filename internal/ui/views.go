@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// View selects which entries the list shows.
+type View int
+
+const (
+	ViewUnread View = iota
+	ViewStarred
+	ViewAll
+	ViewRead
+	viewCount
+)
+
+func (v View) String() string {
+	switch v {
+	case ViewUnread:
+		return "Unread"
+	case ViewStarred:
+		return "Starred"
+	case ViewAll:
+		return "All"
+	case ViewRead:
+		return "Recently read"
+	}
+	return ""
+}
+
+func (v View) Next() View {
+	return (v + 1) % viewCount
+}
+
+func (v View) Prev() View {
+	return (v + viewCount - 1) % viewCount
+}
+
+// query builds the entries query for one page of this view.
+func (v View) query(filter EntryFilter, limit, offset int) miniflux.EntryQuery {
+	q := miniflux.EntryQuery{
+		CategoryID: filter.CategoryID,
+		FeedID:     filter.FeedID,
+		Order:      miniflux.OrderPublishedAt,
+		Direction:  miniflux.DirectionDesc,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	switch v {
+	case ViewUnread:
+		q.Status = []miniflux.ReadStatus{miniflux.ReadStatusUnread}
+	case ViewStarred:
+		starred := true
+		q.Starred = &starred
+	case ViewRead:
+		q.Status = []miniflux.ReadStatus{miniflux.ReadStatusRead}
+		q.Order = miniflux.OrderChangedAt
+	}
+	return q
+}
+
+// entryQuery builds the query for one page of the list, which is a search
+// across every entry when search is set.
+func entryQuery(view View, filter EntryFilter, search string, limit, offset int) miniflux.EntryQuery {
+	if search != "" {
+		return miniflux.EntryQuery{
+			Search:    search,
+			Order:     miniflux.OrderPublishedAt,
+			Direction: miniflux.DirectionDesc,
+			Limit:     limit,
+			Offset:    offset,
+		}
+	}
+	return view.query(filter, limit, offset)
+}
+
+// entryList is the paginated entry list backing a single view.
+type entryList struct {
+	Entries     []miniflux.FeedEntry
+	Total       int
+	NextOffset  int
+	LoadingMore bool
+	Loaded      bool
+	Cursor      int
+}
+
+func (m Model) viewTabs() string {
+	var tabs []string
+	for v := View(0); v < viewCount; v++ {
+		style := m.Styles.Tab
+		if v == m.CurrentView {
+			style = m.Styles.TabActive
+		}
+		tabs = append(tabs, style.Render(v.String()))
+	}
+	return strings.Join(tabs, " ")
+}
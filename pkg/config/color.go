@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// colorNames maps named colors to their ANSI indexes.
+var colorNames = map[string]int{
+	"black":          0,
+	"red":            1,
+	"green":          2,
+	"yellow":         3,
+	"blue":           4,
+	"magenta":        5,
+	"cyan":           6,
+	"white":          7,
+	"gray":           8,
+	"grey":           8,
+	"bright_black":   8,
+	"bright_red":     9,
+	"bright_green":   10,
+	"bright_yellow":  11,
+	"bright_blue":    12,
+	"bright_magenta": 13,
+	"bright_cyan":    14,
+	"bright_white":   15,
+}
+
+// NormalizeColor accepts a hex color ("#ff8800" or "#f80"), an ANSI 256
+// index ("62") or a color name ("cyan", "bright_red"). It returns the hex
+// string or ANSI index as a string, or "" for the terminal default
+// ("reset" or "default").
+func NormalizeColor(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case s == "reset" || s == "default":
+		return "", nil
+	case hexColor.MatchString(s):
+		return s, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return "", fmt.Errorf("ANSI color %d out of range 0-255", n)
+		}
+		return s, nil
+	}
+	if n, ok := colorNames[strings.ReplaceAll(s, "-", "_")]; ok {
+		return strconv.Itoa(n), nil
+	}
+	return "", fmt.Errorf("unknown color %q", s)
+}
@@ -5,36 +5,160 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
+// ThemeConfig selects a color preset and optionally overrides single
+// colors. Colors left empty come from the preset.
 type ThemeConfig struct {
-	UnreadColor string `toml:"unread_color"`
-	ReadColor   string `toml:"read_color"`
+	// Preset is "auto" (follow the terminal background), "dark" or "light".
+	Preset string `toml:"preset"`
+
+	TextColor           string `toml:"text_color,omitempty"`
+	DimColor            string `toml:"dim_color,omitempty"`
+	TitleColor          string `toml:"title_color,omitempty"`
+	UnreadColor         string `toml:"unread_color,omitempty"`
+	ReadColor           string `toml:"read_color,omitempty"`
+	SelectedForeground  string `toml:"selected_fg,omitempty"`
+	SelectedBackground  string `toml:"selected_bg,omitempty"`
+	StatusBarForeground string `toml:"status_bar_fg,omitempty"`
+	StatusBarBackground string `toml:"status_bar_bg,omitempty"`
+	LinkColor           string `toml:"link_color,omitempty"`
+	QuoteColor          string `toml:"quote_color,omitempty"`
+	CodeColor           string `toml:"code_color,omitempty"`
+	ErrorColor          string `toml:"error_color,omitempty"`
+	BorderColor         string `toml:"border_color,omitempty"`
+	MatchForeground     string `toml:"match_fg,omitempty"`
+	MatchBackground     string `toml:"match_bg,omitempty"`
 }
 
 func DefaultThemeConfig() ThemeConfig {
 	return ThemeConfig{
-		UnreadColor: "reset", // Placeholder for actual color codes or hex
-		ReadColor:   "gray",
+		Preset: "auto",
+	}
+}
+
+func (t ThemeConfig) validate() error {
+	switch t.Preset {
+	case "auto", "dark", "light":
+	default:
+		return fmt.Errorf("unknown theme preset %q (want auto, dark or light)", t.Preset)
+	}
+
+	colors := []struct{ name, value string }{
+		{"text_color", t.TextColor},
+		{"dim_color", t.DimColor},
+		{"title_color", t.TitleColor},
+		{"unread_color", t.UnreadColor},
+		{"read_color", t.ReadColor},
+		{"selected_fg", t.SelectedForeground},
+		{"selected_bg", t.SelectedBackground},
+		{"status_bar_fg", t.StatusBarForeground},
+		{"status_bar_bg", t.StatusBarBackground},
+		{"link_color", t.LinkColor},
+		{"quote_color", t.QuoteColor},
+		{"code_color", t.CodeColor},
+		{"error_color", t.ErrorColor},
+		{"border_color", t.BorderColor},
+		{"match_fg", t.MatchForeground},
+		{"match_bg", t.MatchBackground},
+	}
+	var errs []error
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		if _, err := NormalizeColor(c.value); err != nil {
+			errs = append(errs, fmt.Errorf("theme.%s: %w", c.name, err))
+		}
 	}
+	return errors.Join(errs...)
+}
+
+// RetryConfig controls retries of idempotent API requests.
+type RetryConfig struct {
+	MaxRetries int           `toml:"max_retries"`
+	BaseDelay  time.Duration `toml:"base_delay"`
+	MaxDelay   time.Duration `toml:"max_delay"`
+}
+
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// CacheConfig controls the offline entry store.
+type CacheConfig struct {
+	Enabled bool `toml:"enabled"`
+	// Dir holds one database per profile; it defaults to goflux under
+	// the user cache directory ($XDG_CACHE_HOME or ~/.cache).
+	Dir string `toml:"dir,omitempty"`
+	// RetainDays is how long read entries that are not starred are kept.
+	RetainDays int `toml:"retain_days"`
+}
+
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Enabled:    true,
+		RetainDays: 30,
+	}
+}
+
+// FeedConfig holds per-feed overrides, keyed by feed ID in the [feeds]
+// table of the server they belong to.
+type FeedConfig struct {
+	FetchOriginalContent bool `toml:"fetch_original_content"`
 }
 
 type Config struct {
-	ApiKey            string      `toml:"api_key"`
-	ServerUrl         string      `toml:"server_url"`
-	AllowInvalidCerts bool        `toml:"allow_invalid_certs"`
-	Theme             ThemeConfig `toml:"theme"`
+	ServerConfig
+	// DefaultProfile names the profile used when --profile is not given.
+	DefaultProfile string                  `toml:"default_profile,omitempty"`
+	Profiles       map[string]ServerConfig `toml:"profiles,omitempty"`
+	// ActiveProfile is the profile ServerConfig was taken from, or "" for
+	// the top-level settings.
+	ActiveProfile string `toml:"-"`
+	// Warnings are non-fatal problems found while loading.
+	Warnings []string `toml:"-"`
+	// base keeps the top-level server settings so WithProfile can return
+	// to them after switching away.
+	base ServerConfig
+
+	// RequestTimeout bounds each HTTP attempt, e.g. "10s".
+	RequestTimeout time.Duration `toml:"request_timeout"`
+	Retry          RetryConfig   `toml:"retry"`
+	Cache          CacheConfig   `toml:"cache"`
+	// SaveOriginalContent stores fetched original content on the server
+	// so it replaces the feed content for every client.
+	SaveOriginalContent bool        `toml:"save_original_content"`
+	Theme               ThemeConfig `toml:"theme"`
+	// Keys maps action names to the keys that trigger them. Actions not
+	// listed keep their default keys.
+	Keys map[string][]string `toml:"keys,omitempty"`
+}
+
+// Feed returns the overrides for a feed on the active server.
+func (c Config) Feed(feedID int) FeedConfig {
+	return c.Feeds[strconv.Itoa(feedID)]
 }
 
 func DefaultConfig() Config {
 	return Config{
-		ApiKey:            "FIXME",
-		ServerUrl:         "FIXME",
-		AllowInvalidCerts: false,
-		Theme:             DefaultThemeConfig(),
+		ServerConfig: ServerConfig{
+			ServerUrl:         "FIXME",
+			AllowInvalidCerts: false,
+		},
+		RequestTimeout: 10 * time.Second,
+		Retry:          DefaultRetryConfig(),
+		Cache:          DefaultCacheConfig(),
+		Theme:          DefaultThemeConfig(),
+		Keys:           DefaultKeys(),
 	}
 }
 
@@ -49,6 +173,33 @@ func GetConfigFilepath() (string, error) {
 	return path, nil
 }
 
+// CachePath is the offline store for the active profile.
+func (c Config) CachePath() (string, error) {
+	return c.cacheFile(".db")
+}
+
+// CountCachePath holds the unread counts saved by `goflux count --cache`
+// for the active profile.
+func (c Config) CountCachePath() (string, error) {
+	return c.cacheFile("-count.json")
+}
+
+func (c Config) cacheFile(suffix string) (string, error) {
+	dir := expandHome(c.Cache.Dir)
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find user cache dir: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "goflux")
+	}
+	name := c.ActiveProfile
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(dir, name+suffix), nil
+}
+
 func Init() (string, error) {
 	path, err := GetConfigFilepath()
 	if err != nil {
@@ -64,12 +215,25 @@ func Init() (string, error) {
 		return "", err
 	}
 
-	f, err := os.Create(path)
+	// The file may end up holding an API key, so keep it private
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
 	if err != nil {
 		return "", err
 	}
 	defer f.Close()
 
+	header := "# Set api_key, or read it from a command (api_key_command = \"pass show miniflux\"),\n" +
+		"# a file (api_key_file = \"~/.config/cliflux/api_key\") or the " + EnvAPIKey + " environment variable.\n" +
+		"# For HTTP Basic auth set auth = \"basic\" with username and password (or password_command /\n" +
+		"# password_file). Extra headers for a reverse proxy go in a [headers] table.\n" +
+		"# Per-feed settings go in a [feeds] table keyed by feed ID, e.g.\n" +
+		"#   [feeds.12]\n" +
+		"#   fetch_original_content = true\n" +
+		"# Feed IDs differ between servers, so a profile has its own [profiles.<name>.feeds].\n\n"
+	if _, err := f.WriteString(header); err != nil {
+		return "", err
+	}
+
 	encoder := toml.NewEncoder(f)
 	if err := encoder.Encode(DefaultConfig()); err != nil {
 		return "", err
@@ -78,7 +242,11 @@ func Init() (string, error) {
 	return path, nil
 }
 
-func Load() (Config, error) {
+// Load reads the config file and selects the given profile, or
+// default_profile when profile is empty. Credentials are left to
+// ResolveCredentials so commands that never reach the server do not run
+// api_key_command.
+func Load(profile string) (Config, error) {
 	path, err := GetConfigFilepath()
 	if err != nil {
 		return Config{}, err
@@ -89,24 +257,60 @@ func Load() (Config, error) {
 	}
 
 	var cfg Config
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+	md, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
 		return Config{}, fmt.Errorf("error parsing config file: %w", err)
 	}
 
-	// Validate/Clean URL
-	cfg.ServerUrl = strings.TrimSpace(cfg.ServerUrl)
-	if cfg.ServerUrl == "" {
-		return Config{}, errors.New("server_url cannot be empty")
+	if profile == "" {
+		profile = cfg.DefaultProfile
+	}
+	if w := literalKeyWarning(path, cfg); w != "" {
+		cfg.Warnings = append(cfg.Warnings, w)
+	}
+
+	// Environment overrides apply to whichever server is selected at startup
+	cfg.base = cfg.ServerConfig
+	if profile == "" {
+		cfg.base.applyEnv()
+	} else if p, ok := cfg.Profiles[profile]; ok {
+		p.applyEnv()
+		cfg.Profiles[profile] = p
+	}
+	cfg, err = cfg.selectProfile(profile)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := validateKeys(cfg.Keys); err != nil {
+		return Config{}, fmt.Errorf("invalid [keys] in %s:\n%w", path, err)
+	}
+
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = DefaultConfig().RequestTimeout
+	}
+	if !md.IsDefined("retry", "max_retries") {
+		cfg.Retry.MaxRetries = DefaultRetryConfig().MaxRetries
+	}
+	if cfg.Retry.BaseDelay <= 0 {
+		cfg.Retry.BaseDelay = DefaultRetryConfig().BaseDelay
+	}
+	if cfg.Retry.MaxDelay <= 0 {
+		cfg.Retry.MaxDelay = DefaultRetryConfig().MaxDelay
+	}
+
+	if !md.IsDefined("cache", "enabled") {
+		cfg.Cache.Enabled = DefaultCacheConfig().Enabled
 	}
-	if strings.HasSuffix(cfg.ServerUrl, "/") {
-		cfg.ServerUrl = strings.TrimSuffix(cfg.ServerUrl, "/")
+	if cfg.Cache.RetainDays <= 0 {
+		cfg.Cache.RetainDays = DefaultCacheConfig().RetainDays
 	}
 
-	if cfg.Theme.UnreadColor == "" {
-		cfg.Theme.UnreadColor = DefaultThemeConfig().UnreadColor
+	if cfg.Theme.Preset == "" {
+		cfg.Theme.Preset = DefaultThemeConfig().Preset
 	}
-	if cfg.Theme.ReadColor == "" {
-		cfg.Theme.ReadColor = DefaultThemeConfig().ReadColor
+	if err := cfg.Theme.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid [theme] in %s:\n%w", path, err)
 	}
 
 	return cfg, nil
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KeyContext is the set of screens an action is active on. Two actions
+// may share a key only if they are never active on the same screen.
+type KeyContext uint8
+
+const (
+	KeyContextList KeyContext = 1 << iota
+	KeyContextReader
+	KeyContextFeeds
+	KeyContextProfiles
+
+	KeyContextAll = KeyContextList | KeyContextReader | KeyContextFeeds | KeyContextProfiles
+)
+
+type keyAction struct {
+	Name     string
+	Keys     []string
+	Contexts KeyContext
+}
+
+// keyActions lists every action that can be bound in the [keys] table.
+var keyActions = []keyAction{
+	{"up", []string{"up", "k"}, KeyContextAll},
+	{"down", []string{"down", "j"}, KeyContextAll},
+	{"enter", []string{"enter"}, KeyContextList | KeyContextFeeds | KeyContextProfiles},
+	{"back", []string{"esc", "b"}, KeyContextAll},
+	{"quit", []string{"q", "ctrl+c"}, KeyContextAll},
+	{"refresh", []string{"r"}, KeyContextList | KeyContextFeeds},
+	{"toggle_read", []string{"u"}, KeyContextReader},
+	{"toggle_read_list", []string{"m"}, KeyContextList},
+	{"toggle_star", []string{"s"}, KeyContextList | KeyContextReader},
+	{"mark_all_read", []string{"A"}, KeyContextList},
+	{"help", []string{"?"}, KeyContextAll},
+	{"save", []string{"e"}, KeyContextList | KeyContextReader},
+	{"open_browser", []string{"o"}, KeyContextList | KeyContextReader},
+	{"feeds", []string{"f"}, KeyContextList},
+	{"next_view", []string{"tab"}, KeyContextList},
+	{"prev_view", []string{"shift+tab"}, KeyContextList},
+	{"search", []string{"/"}, KeyContextList},
+	{"next_match", []string{"n"}, KeyContextReader},
+	{"prev_match", []string{"N"}, KeyContextReader},
+	{"fetch_original", []string{"c"}, KeyContextReader},
+	{"undo", []string{"U"}, KeyContextList},
+	{"profiles", []string{"P"}, KeyContextList},
+	{"add_feed", []string{"a"}, KeyContextFeeds},
+	{"edit_feed", []string{"e"}, KeyContextFeeds},
+	{"delete_feed", []string{"d"}, KeyContextFeeds},
+	{"add_category", []string{"C"}, KeyContextFeeds},
+	{"select_feed", []string{"x"}, KeyContextFeeds},
+	{"move_feeds", []string{"M"}, KeyContextFeeds},
+}
+
+// DefaultKeys maps each action name to its default keys.
+func DefaultKeys() map[string][]string {
+	keys := make(map[string][]string, len(keyActions))
+	for _, action := range keyActions {
+		keys[action.Name] = append([]string(nil), action.Keys...)
+	}
+	return keys
+}
+
+// KeyBindings returns the default keys with the [keys] table applied.
+func (c Config) KeyBindings() map[string][]string {
+	keys := DefaultKeys()
+	for name, bound := range c.Keys {
+		keys[name] = bound
+	}
+	return keys
+}
+
+// validateKeys reports unknown actions, actions left without keys and
+// keys bound to two actions that are active on the same screen.
+func validateKeys(overrides map[string][]string) error {
+	known := make(map[string]bool, len(keyActions))
+	for _, action := range keyActions {
+		known[action.Name] = true
+	}
+
+	var errs []error
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch {
+		case !known[name]:
+			errs = append(errs, fmt.Errorf("unknown action %q in [keys]", name))
+		case len(overrides[name]) == 0:
+			errs = append(errs, fmt.Errorf("action %q in [keys] has no keys", name))
+		}
+		for _, k := range overrides[name] {
+			if strings.TrimSpace(k) == "" {
+				errs = append(errs, fmt.Errorf("action %q in [keys] has an empty key", name))
+			}
+		}
+	}
+
+	bound := Config{Keys: overrides}.KeyBindings()
+	for i, a := range keyActions {
+		for _, b := range keyActions[i+1:] {
+			if a.Contexts&b.Contexts == 0 {
+				continue
+			}
+			for _, k := range bound[a.Name] {
+				for _, other := range bound[b.Name] {
+					if k == other {
+						errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s", k, a.Name, b.Name))
+					}
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
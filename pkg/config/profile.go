@@ -0,0 +1,142 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ServerConfig holds the settings needed to reach one Miniflux server.
+// The top level of the config file is one ServerConfig and each
+// [profiles.<name>] table is another.
+type ServerConfig struct {
+	ApiKey string `toml:"api_key"`
+	// ApiKeyCommand is run through the shell and the first line of its
+	// output used as the key, e.g. "pass show miniflux".
+	ApiKeyCommand string `toml:"api_key_command,omitempty"`
+	ApiKeyFile    string `toml:"api_key_file,omitempty"`
+	// Auth is "token" (the default, using the API key) or "basic" for
+	// username and password.
+	Auth            string `toml:"auth,omitempty"`
+	Username        string `toml:"username,omitempty"`
+	Password        string `toml:"password,omitempty"`
+	PasswordCommand string `toml:"password_command,omitempty"`
+	PasswordFile    string `toml:"password_file,omitempty"`
+	// Headers are sent with every request, e.g. reverse proxy tokens.
+	Headers           map[string]string `toml:"headers,omitempty"`
+	ServerUrl         string            `toml:"server_url"`
+	AllowInvalidCerts bool              `toml:"allow_invalid_certs"`
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile     string `toml:"ca_file,omitempty"`
+	ClientCert string `toml:"client_cert,omitempty"`
+	ClientKey  string `toml:"client_key,omitempty"`
+	// TLSMinVersion is "1.0", "1.1", "1.2" or "1.3".
+	TLSMinVersion string `toml:"tls_min_version,omitempty"`
+	// PinnedSHA256 lists SHA-256 hashes of the server's public key (SPKI),
+	// in base64 or hex. One of them must appear in the certificate chain.
+	PinnedSHA256 []string `toml:"pinned_sha256,omitempty"`
+	// Feeds holds per-feed overrides. Feed IDs are only unique on one
+	// server, so each profile has its own table.
+	Feeds map[string]FeedConfig `toml:"feeds,omitempty"`
+	// ProxyURL overrides HTTP_PROXY/HTTPS_PROXY, e.g. "socks5://127.0.0.1:1080".
+	ProxyURL string `toml:"proxy_url,omitempty"`
+	// UnixSocket reaches the server over a Unix domain socket; server_url
+	// then only supplies the host name and path, and defaults to
+	// http://localhost.
+	UnixSocket string `toml:"unix_socket,omitempty"`
+
+	// keyFromEnv is set when GOFLUX_API_KEY replaced the configured key.
+	keyFromEnv bool
+}
+
+func (s *ServerConfig) normalize() error {
+	// Validate/Clean URL
+	s.ServerUrl = strings.TrimSpace(s.ServerUrl)
+	if s.ServerUrl == "" && s.UnixSocket != "" {
+		s.ServerUrl = "http://localhost"
+	}
+	if s.ServerUrl == "" {
+		return errors.New("server_url cannot be empty")
+	}
+	s.ServerUrl = strings.TrimSuffix(s.ServerUrl, "/")
+
+	if (s.ClientCert == "") != (s.ClientKey == "") {
+		return errors.New("client_cert and client_key must be set together")
+	}
+	switch s.TLSMinVersion {
+	case "", "1.0", "1.1", "1.2", "1.3":
+	default:
+		return fmt.Errorf("unknown tls_min_version %q (want 1.0, 1.1, 1.2 or 1.3)", s.TLSMinVersion)
+	}
+	if s.ProxyURL != "" && s.UnixSocket != "" {
+		return errors.New("proxy_url and unix_socket cannot be used together")
+	}
+	s.UnixSocket = expandHome(s.UnixSocket)
+	s.CAFile = expandHome(s.CAFile)
+	s.ClientCert = expandHome(s.ClientCert)
+	s.ClientKey = expandHome(s.ClientKey)
+	return nil
+}
+
+// ProfileNames lists the configured profiles in alphabetical order.
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunsCommand reports whether connecting to the named profile runs an
+// api_key_command or password_command, which may prompt on the terminal.
+func (c Config) RunsCommand(name string) bool {
+	server := c.base
+	if name != "" {
+		server = c.Profiles[name]
+	}
+	return server.ApiKeyCommand != "" || server.PasswordCommand != ""
+}
+
+// WithProfile returns a copy of c connected to the named profile. The
+// empty name selects the server settings at the top of the file.
+func (c Config) WithProfile(name string) (Config, error) {
+	c, err := c.selectProfile(name)
+	if err != nil {
+		return Config{}, err
+	}
+	return c.ResolveCredentials()
+}
+
+// ResolveCredentials fills in the active server's API key or password,
+// running api_key_command or password_command if one is set.
+func (c Config) ResolveCredentials() (Config, error) {
+	if err := c.ServerConfig.resolveCredentials(); err != nil {
+		if c.ActiveProfile != "" {
+			return Config{}, fmt.Errorf("profile %q: %w", c.ActiveProfile, err)
+		}
+		return Config{}, err
+	}
+	return c, nil
+}
+
+func (c Config) selectProfile(name string) (Config, error) {
+	server := c.base
+	if name != "" {
+		p, ok := c.Profiles[name]
+		if !ok {
+			return Config{}, fmt.Errorf("unknown profile %q", name)
+		}
+		server = p
+	}
+	if err := server.normalize(); err != nil {
+		if name != "" {
+			return Config{}, fmt.Errorf("profile %q: %w", name, err)
+		}
+		return Config{}, err
+	}
+	c.ServerConfig = server
+	c.ActiveProfile = name
+	return c, nil
+}
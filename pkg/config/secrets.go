@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment variables that override the server settings selected at
+// startup. They take precedence over everything in the config file.
+const (
+	EnvAPIKey    = "GOFLUX_API_KEY"
+	EnvServerURL = "GOFLUX_SERVER_URL"
+)
+
+// applyEnv overrides s with the GOFLUX_* environment variables.
+func (s *ServerConfig) applyEnv() {
+	if v := os.Getenv(EnvServerURL); v != "" {
+		s.ServerUrl = v
+	}
+	if v := os.Getenv(EnvAPIKey); v != "" {
+		s.ApiKey = v
+		s.ApiKeyCommand = ""
+		s.ApiKeyFile = ""
+		s.keyFromEnv = true
+	}
+}
+
+// CredentialSource names the setting the server's API key or password
+// was read from, for error messages.
+func (s ServerConfig) CredentialSource() string {
+	if s.Auth == "basic" {
+		switch {
+		case s.PasswordCommand != "":
+			return "password_command"
+		case s.PasswordFile != "":
+			return "password_file"
+		}
+		return "username and password"
+	}
+	switch {
+	case s.keyFromEnv:
+		return EnvAPIKey
+	case s.ApiKeyCommand != "":
+		return "api_key_command"
+	case s.ApiKeyFile != "":
+		return "api_key_file"
+	}
+	return "api_key"
+}
+
+// resolveCredentials fills in the secrets the selected auth method needs.
+func (s *ServerConfig) resolveCredentials() error {
+	switch s.Auth {
+	case "", "token":
+		key, err := resolveSecret(s.ApiKey, s.ApiKeyCommand, s.ApiKeyFile)
+		if err != nil {
+			return fmt.Errorf("api key: %w", err)
+		}
+		if key == "" {
+			return fmt.Errorf("no API key: set api_key, api_key_command, api_key_file or %s", EnvAPIKey)
+		}
+		s.ApiKey = key
+	case "basic":
+		if s.Username == "" {
+			return errors.New(`auth = "basic" needs a username`)
+		}
+		password, err := resolveSecret(s.Password, s.PasswordCommand, s.PasswordFile)
+		if err != nil {
+			return fmt.Errorf("password: %w", err)
+		}
+		if password == "" {
+			return errors.New(`auth = "basic" needs password, password_command or password_file`)
+		}
+		s.Password = password
+	default:
+		return fmt.Errorf("unknown auth %q (want token or basic)", s.Auth)
+	}
+	return nil
+}
+
+// resolveSecret returns the secret from the first configured source, in
+// order: command, file, then the literal value.
+func resolveSecret(literal, command, file string) (string, error) {
+	switch {
+	case command != "":
+		secret, err := runKeyCommand(command)
+		if err != nil {
+			return "", fmt.Errorf("command: %w", err)
+		}
+		return secret, nil
+	case file != "":
+		data, err := os.ReadFile(expandHome(file))
+		if err != nil {
+			return "", fmt.Errorf("file: %w", err)
+		}
+		return firstLine(data), nil
+	}
+	return strings.TrimSpace(literal), nil
+}
+
+// runKeyCommand runs command through the shell and returns the first
+// line of its output, so tools like `pass show` that print extra lines
+// after the secret work as-is.
+func runKeyCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	key := firstLine(out)
+	if key == "" {
+		return "", fmt.Errorf("%q printed nothing", command)
+	}
+	return key, nil
+}
+
+func firstLine(data []byte) string {
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// literalKeyWarning warns when the config file stores an API key in
+// plain text and other users can read it.
+func literalKeyWarning(path string, cfg Config) string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	hasLiteral := cfg.ApiKey != "" || cfg.Password != ""
+	for _, p := range cfg.Profiles {
+		hasLiteral = hasLiteral || p.ApiKey != "" || p.Password != ""
+	}
+	info, err := os.Stat(path)
+	if !hasLiteral || err != nil || info.Mode().Perm()&0o004 == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s contains an api_key or password and is world-readable; run chmod 600 on it or use api_key_command, api_key_file or %s", path, EnvAPIKey)
+}
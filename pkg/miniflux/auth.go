@@ -0,0 +1,45 @@
+package miniflux
+
+import "net/http"
+
+// Authenticator adds credentials to every request the client sends.
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// TokenAuth authenticates with a Miniflux API key.
+type TokenAuth string
+
+func (t TokenAuth) Authenticate(req *http.Request) {
+	req.Header.Set("X-Auth-Token", string(t))
+}
+
+// BasicAuth authenticates with a Miniflux username and password.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (b BasicAuth) Authenticate(req *http.Request) {
+	req.SetBasicAuth(b.Username, b.Password)
+}
+
+// HeaderAuth sets arbitrary headers, such as the service token headers a
+// reverse proxy like Cloudflare Access expects in front of Miniflux.
+type HeaderAuth map[string]string
+
+func (h HeaderAuth) Authenticate(req *http.Request) {
+	for name, value := range h {
+		req.Header.Set(name, value)
+	}
+}
+
+// MultiAuth applies several authenticators in order, e.g. proxy headers
+// plus a Miniflux API key.
+type MultiAuth []Authenticator
+
+func (m MultiAuth) Authenticate(req *http.Request) {
+	for _, a := range m {
+		a.Authenticate(req)
+	}
+}
@@ -0,0 +1,40 @@
+package miniflux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+func (c *Client) CreateCategory(ctx context.Context, title string) (Category, error) {
+	resp, err := c.doRequest(ctx, "POST", "/v1/categories", map[string]string{"title": title})
+	if err != nil {
+		return Category{}, err
+	}
+
+	var result Category
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return Category{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) UpdateCategory(ctx context.Context, categoryID int, title string) (Category, error) {
+	path := fmt.Sprintf("/v1/categories/%d", categoryID)
+	resp, err := c.doRequest(ctx, "PUT", path, map[string]string{"title": title})
+	if err != nil {
+		return Category{}, err
+	}
+
+	var result Category
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return Category{}, err
+	}
+	return result, nil
+}
+
+// DeleteCategory removes a category. The server deletes its feeds with it.
+func (c *Client) DeleteCategory(ctx context.Context, categoryID int) error {
+	_, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/v1/categories/%d", categoryID), nil)
+	return err
+}
@@ -2,6 +2,7 @@ package miniflux
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -12,126 +13,286 @@ import (
 
 type Client struct {
 	baseURL    string
-	apiKey     string
+	auth       Authenticator
 	httpClient *http.Client
+	retry      RetryPolicy
+	// err is set when an option could not be applied; every request
+	// returns it.
+	err error
 }
 
-func NewClient(serverURL, apiKey string, allowInvalidCerts bool) *Client {
+type Option func(*Client)
+
+// WithTimeout bounds each HTTP attempt. Zero disables the timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = d
+	}
+}
+
+// WithAuth replaces the default API key authentication.
+func WithAuth(a Authenticator) Option {
+	return func(c *Client) {
+		c.auth = a
+	}
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+func NewClient(serverURL, apiKey string, allowInvalidCerts bool, opts ...Option) *Client {
 	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: allowInvalidCerts},
 	}
 	client := &http.Client{
 		Transport: tr,
 		Timeout:   10 * time.Second,
 	}
-	return &Client{
+	c := &Client{
 		baseURL:    serverURL,
-		apiKey:     apiKey,
+		auth:       TokenAuth(apiKey),
 		httpClient: client,
+		retry:      DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-func (c *Client) doRequest(method, path string, body interface{}) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", c.baseURL, path)
+// doRequest sends a request, retrying idempotent methods on 5xx, 429 and
+// network errors according to the client's RetryPolicy.
+func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
+	return c.request(ctx, method, path, body, isIdempotent(method))
+}
 
-	var reqBody io.Reader
+func (c *Client) request(ctx context.Context, method, path string, body interface{}, retry bool) ([]byte, error) {
+	var payload []byte
 	if body != nil {
 		jsonBytes, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonBytes)
+		payload = jsonBytes
+	}
+	return c.requestRaw(ctx, method, path, "application/json", payload, retry)
+}
+
+// requestRaw sends payload as the request body unchanged.
+func (c *Client) requestRaw(ctx context.Context, method, path, contentType string, payload []byte, retry bool) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	for attempt := 0; ; attempt++ {
+		respBytes, resp, err := c.send(ctx, method, path, contentType, payload)
+		if err == nil && resp.StatusCode < 400 {
+			return respBytes, nil
+		}
+		if err == nil {
+			err = newAPIError(method, path, resp.StatusCode, respBytes)
+		}
+
+		if !retry || attempt >= c.retry.MaxRetries {
+			return nil, err
+		}
+		wait := c.retry.backoff(attempt)
+		if resp != nil {
+			if !isRetryableStatus(resp.StatusCode) {
+				return nil, err
+			}
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if d > c.retry.MaxDelay {
+					return nil, err
+				}
+				wait = d
+			}
+		} else if !isRetryableError(ctx, err) {
+			return nil, err
+		}
+
+		if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+}
+
+// send performs a single HTTP attempt. resp is nil when the request never
+// got a response; its body has already been read into the returned bytes.
+func (c *Client) send(ctx context.Context, method, path, contentType string, payload []byte) ([]byte, *http.Response, error) {
+	url := fmt.Sprintf("%s%s", c.baseURL, path)
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	req.Header.Set("X-Auth-Token", c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", "goflux-go/0.1")
+	c.auth.Authenticate(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	respBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return respBytes, resp, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("api error: %s (status: %d)", string(respBytes), resp.StatusCode)
+// GetEntries lists entries matching q, returning the page and the total
+// number of matches.
+func (c *Client) GetEntries(ctx context.Context, q EntryQuery) ([]FeedEntry, int, error) {
+	resp, err := c.doRequest(ctx, "GET", "/v1/entries?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return respBytes, nil
+	var result FeedEntriesResponse
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, 0, err
+	}
+	return result.Entries, result.Total, nil
+}
+
+func (c *Client) GetEntry(ctx context.Context, entryID int) (FeedEntry, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/v1/entries/%d", entryID), nil)
+	if err != nil {
+		return FeedEntry{}, err
+	}
+
+	var result FeedEntry
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return FeedEntry{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetUnreadEntries(ctx context.Context, limit, offset int) ([]FeedEntry, int, error) {
+	return c.GetEntries(ctx, EntryQuery{
+		Status:    []ReadStatus{ReadStatusUnread},
+		Order:     OrderPublishedAt,
+		Direction: DirectionDesc,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+func (c *Client) GetStarredEntries(ctx context.Context, limit, offset int) ([]FeedEntry, int, error) {
+	starred := true
+	return c.GetEntries(ctx, EntryQuery{
+		Starred:   &starred,
+		Order:     OrderPublishedAt,
+		Direction: DirectionDesc,
+		Limit:     limit,
+		Offset:    offset,
+	})
 }
 
-func (c *Client) GetUnreadEntries(limit, offset int) ([]FeedEntry, error) {
-	path := fmt.Sprintf("/v1/entries?status=unread&order=published_at&direction=desc&limit=%d&offset=%d", limit, offset)
-	resp, err := c.doRequest("GET", path, nil)
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	resp, err := c.doRequest(ctx, "GET", "/v1/categories", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var result FeedEntriesResponse
+	var result []Category
 	if err := json.Unmarshal(resp, &result); err != nil {
 		return nil, err
 	}
-	return result.Entries, nil
+	return result, nil
 }
 
-func (c *Client) GetStarredEntries(limit, offset int) ([]FeedEntry, error) {
-	path := fmt.Sprintf("/v1/entries?starred=true&order=published_at&direction=desc&limit=%d&offset=%d", limit, offset)
-	resp, err := c.doRequest("GET", path, nil)
+func (c *Client) GetFeeds(ctx context.Context) ([]Feed, error) {
+	resp, err := c.doRequest(ctx, "GET", "/v1/feeds", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var result FeedEntriesResponse
+	var result []Feed
 	if err := json.Unmarshal(resp, &result); err != nil {
 		return nil, err
 	}
-	return result.Entries, nil
+	return result, nil
 }
 
-func (c *Client) ChangeEntryReadStatus(entryIDs []int, status ReadStatus) error {
+func (c *Client) GetFeedCounters(ctx context.Context) (FeedCounters, error) {
+	resp, err := c.doRequest(ctx, "GET", "/v1/feeds/counters", nil)
+	if err != nil {
+		return FeedCounters{}, err
+	}
+
+	var result FeedCounters
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return FeedCounters{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) ChangeEntryReadStatus(ctx context.Context, entryIDs []int, status ReadStatus) error {
 	req := UpdateEntriesRequest{
 		Status:   string(status),
 		EntryIDs: entryIDs,
 	}
-	_, err := c.doRequest("PUT", "/v1/entries", req)
+	_, err := c.doRequest(ctx, "PUT", "/v1/entries", req)
 	return err
 }
 
-func (c *Client) ToggleStarred(entryID int) error {
+func (c *Client) ToggleStarred(ctx context.Context, entryID int) error {
 	path := fmt.Sprintf("/v1/entries/%d/bookmark", entryID)
-	_, err := c.doRequest("PUT", path, nil)
+	// The bookmark endpoint toggles, so a retried PUT could undo itself
+	_, err := c.request(ctx, "PUT", path, nil, false)
 	return err
 }
 
-func (c *Client) SaveEntry(entryID int) error {
+func (c *Client) SaveEntry(ctx context.Context, entryID int) error {
 	path := fmt.Sprintf("/v1/entries/%d/save", entryID)
 	// Original Rust used POST for save
-	_, err := c.doRequest("POST", path, nil)
+	_, err := c.doRequest(ctx, "POST", path, nil)
+	return err
+}
+
+func (c *Client) MarkAllAsRead(ctx context.Context, entryIDs []int) error {
+	return c.ChangeEntryReadStatus(ctx, entryIDs, ReadStatusRead)
+}
+
+func (c *Client) MarkFeedAsRead(ctx context.Context, feedID int) error {
+	path := fmt.Sprintf("/v1/feeds/%d/mark-all-as-read", feedID)
+	_, err := c.doRequest(ctx, "PUT", path, nil)
 	return err
 }
 
-func (c *Client) MarkAllAsRead(entryIDs []int) error {
-	return c.ChangeEntryReadStatus(entryIDs, ReadStatusRead)
+func (c *Client) MarkCategoryAsRead(ctx context.Context, categoryID int) error {
+	path := fmt.Sprintf("/v1/categories/%d/mark-all-as-read", categoryID)
+	_, err := c.doRequest(ctx, "PUT", path, nil)
+	return err
 }
 
-func (c *Client) RefreshAllFeeds() error {
-	_, err := c.doRequest("PUT", "/v1/feeds/refresh", nil)
+func (c *Client) RefreshAllFeeds(ctx context.Context) error {
+	_, err := c.doRequest(ctx, "PUT", "/v1/feeds/refresh", nil)
 	return err
 }
 
-func (c *Client) FetchOriginalContent(entryID int) (string, error) {
+// FetchOriginalContent downloads the full article for an entry. When
+// updateContent is set the server also replaces the stored entry content.
+func (c *Client) FetchOriginalContent(ctx context.Context, entryID int, updateContent bool) (string, error) {
 	path := fmt.Sprintf("/v1/entries/%d/fetch-content", entryID)
-	resp, err := c.doRequest("GET", path, nil)
+	if updateContent {
+		path += "?update_content=true"
+	}
+	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,67 @@
+package miniflux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned for any response with a 4xx or 5xx status.
+type APIError struct {
+	StatusCode int
+	// Message is the server's error_message, or the raw body if the
+	// response was not a Miniflux JSON error.
+	Message string
+	Method  string
+	Path    string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("api error: %s %s: %s (status: %d)", e.Method, e.Path, msg, e.StatusCode)
+}
+
+func newAPIError(method, path string, status int, body []byte) *APIError {
+	var payload struct {
+		ErrorMessage string `json:"error_message"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &payload); err == nil && payload.ErrorMessage != "" {
+		msg = payload.ErrorMessage
+	}
+	return &APIError{
+		StatusCode: status,
+		Message:    msg,
+		Method:     method,
+		Path:       path,
+	}
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// IsUnauthorized reports whether the server rejected the credentials.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsServerError reports whether the server failed with a 5xx status.
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
@@ -0,0 +1,52 @@
+package miniflux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"miniflux error", http.StatusBadRequest, `{"error_message":"This feed already exists."}`, "This feed already exists."},
+		{"other json", http.StatusBadRequest, `{"detail":"nope"}`, `{"detail":"nope"}`},
+		{"plain text", http.StatusBadGateway, "  Bad Gateway\n", "Bad Gateway"},
+		{"empty body", http.StatusServiceUnavailable, "", ""},
+	} {
+		err := newAPIError("GET", "/v1/feeds", tc.status, []byte(tc.body))
+		if err.Message != tc.want || err.StatusCode != tc.status {
+			t.Errorf("%s: got %d %q, want %d %q", tc.name, err.StatusCode, err.Message, tc.status, tc.want)
+		}
+	}
+
+	err := newAPIError("GET", "/v1/feeds", http.StatusServiceUnavailable, nil)
+	if want := "api error: GET /v1/feeds: Service Unavailable (status: 503)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	wrap := func(status int) error {
+		return fmt.Errorf("load: %w", newAPIError("GET", "/", status, nil))
+	}
+	for _, tc := range []struct {
+		name string
+		is   func(error) bool
+		yes  int
+		no   int
+	}{
+		{"unauthorized", IsUnauthorized, http.StatusUnauthorized, http.StatusForbidden},
+		{"not found", IsNotFound, http.StatusNotFound, http.StatusGone},
+		{"rate limited", IsRateLimited, http.StatusTooManyRequests, http.StatusBadRequest},
+		{"server error", IsServerError, http.StatusBadGateway, http.StatusNotFound},
+	} {
+		if !tc.is(wrap(tc.yes)) || tc.is(wrap(tc.no)) || tc.is(fmt.Errorf("no response")) {
+			t.Errorf("%s: wrong classification", tc.name)
+		}
+	}
+}
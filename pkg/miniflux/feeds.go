@@ -0,0 +1,73 @@
+package miniflux
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Discover finds the feeds a website advertises.
+func (c *Client) Discover(ctx context.Context, siteURL string) ([]Subscription, error) {
+	resp, err := c.doRequest(ctx, "POST", "/v1/discover", map[string]string{"url": siteURL})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Subscription
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) GetFeed(ctx context.Context, feedID int) (Feed, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/v1/feeds/%d", feedID), nil)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	var result Feed
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return Feed{}, err
+	}
+	return result, nil
+}
+
+// CreateFeed subscribes to a feed and returns its ID.
+func (c *Client) CreateFeed(ctx context.Context, req FeedCreationRequest) (int, error) {
+	resp, err := c.doRequest(ctx, "POST", "/v1/feeds", req)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		FeedID int `json:"feed_id"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return 0, err
+	}
+	return result.FeedID, nil
+}
+
+func (c *Client) UpdateFeed(ctx context.Context, feedID int, req FeedModificationRequest) (Feed, error) {
+	resp, err := c.doRequest(ctx, "PUT", fmt.Sprintf("/v1/feeds/%d", feedID), req)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	var result Feed
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return Feed{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) DeleteFeed(ctx context.Context, feedID int) error {
+	_, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/v1/feeds/%d", feedID), nil)
+	return err
+}
+
+func (c *Client) RefreshFeed(ctx context.Context, feedID int) error {
+	_, err := c.doRequest(ctx, "PUT", fmt.Sprintf("/v1/feeds/%d/refresh", feedID), nil)
+	return err
+}
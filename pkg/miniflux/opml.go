@@ -0,0 +1,15 @@
+package miniflux
+
+import "context"
+
+// Export returns every subscription as an OPML document.
+func (c *Client) Export(ctx context.Context) ([]byte, error) {
+	return c.doRequest(ctx, "GET", "/v1/export", nil)
+}
+
+// Import subscribes to the feeds in an OPML document, creating missing
+// categories. Feeds that already exist are skipped by the server.
+func (c *Client) Import(ctx context.Context, opml []byte) error {
+	_, err := c.requestRaw(ctx, "POST", "/v1/import", "text/xml", opml, false)
+	return err
+}
@@ -0,0 +1,108 @@
+package miniflux
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type EntryOrder string
+
+const (
+	OrderID            EntryOrder = "id"
+	OrderStatus        EntryOrder = "status"
+	OrderPublishedAt   EntryOrder = "published_at"
+	OrderChangedAt     EntryOrder = "changed_at"
+	OrderCreatedAt     EntryOrder = "created_at"
+	OrderCategoryTitle EntryOrder = "category_title"
+	OrderCategoryID    EntryOrder = "category_id"
+	OrderTitle         EntryOrder = "title"
+	OrderAuthor        EntryOrder = "author"
+)
+
+type SortDirection string
+
+const (
+	DirectionAsc  SortDirection = "asc"
+	DirectionDesc SortDirection = "desc"
+)
+
+// EntryQuery holds the filters accepted by the /v1/entries endpoint.
+// Zero-valued fields are left out of the request.
+type EntryQuery struct {
+	Status []ReadStatus
+	// Starred filters on the bookmark flag when non-nil.
+	Starred *bool
+	Search  string
+
+	CategoryID int
+	FeedID     int
+
+	Before          time.Time
+	After           time.Time
+	PublishedBefore time.Time
+	PublishedAfter  time.Time
+	ChangedBefore   time.Time
+	ChangedAfter    time.Time
+	BeforeEntryID   int
+	AfterEntryID    int
+
+	Order     EntryOrder
+	Direction SortDirection
+	Limit     int
+	Offset    int
+
+	GloballyVisible bool
+}
+
+// Values encodes the query as URL parameters.
+func (q EntryQuery) Values() url.Values {
+	v := url.Values{}
+	for _, status := range q.Status {
+		v.Add("status", string(status))
+	}
+	if q.Starred != nil {
+		v.Set("starred", strconv.FormatBool(*q.Starred))
+	}
+	if q.Search != "" {
+		v.Set("search", q.Search)
+	}
+	setInt(v, "category_id", q.CategoryID)
+	setInt(v, "feed_id", q.FeedID)
+	setTime(v, "before", q.Before)
+	setTime(v, "after", q.After)
+	setTime(v, "published_before", q.PublishedBefore)
+	setTime(v, "published_after", q.PublishedAfter)
+	setTime(v, "changed_before", q.ChangedBefore)
+	setTime(v, "changed_after", q.ChangedAfter)
+	setInt(v, "before_entry_id", q.BeforeEntryID)
+	setInt(v, "after_entry_id", q.AfterEntryID)
+	if q.Order != "" {
+		v.Set("order", string(q.Order))
+	}
+	if q.Direction != "" {
+		v.Set("direction", string(q.Direction))
+	}
+	setInt(v, "limit", q.Limit)
+	setInt(v, "offset", q.Offset)
+	if q.GloballyVisible {
+		v.Set("globally_visible", "true")
+	}
+	return v
+}
+
+func (q EntryQuery) Encode() string {
+	return q.Values().Encode()
+}
+
+func setInt(v url.Values, key string, n int) {
+	if n != 0 {
+		v.Set(key, strconv.Itoa(n))
+	}
+}
+
+func setTime(v url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		v.Set(key, strconv.FormatInt(t.Unix(), 10))
+	}
+}
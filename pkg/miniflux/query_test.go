@@ -0,0 +1,46 @@
+package miniflux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryQueryEncode(t *testing.T) {
+	starred, unstarred := true, false
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		q    EntryQuery
+		want string
+	}{
+		{"empty", EntryQuery{}, ""},
+		{
+			"statuses",
+			EntryQuery{Status: []ReadStatus{ReadStatusUnread, ReadStatusRead}},
+			"status=unread&status=read",
+		},
+		{"starred", EntryQuery{Starred: &starred}, "starred=true"},
+		{"not starred", EntryQuery{Starred: &unstarred}, "starred=false"},
+		{"search", EntryQuery{Search: "go & rust"}, "search=go+%26+rust"},
+		{
+			"filters",
+			EntryQuery{CategoryID: 3, FeedID: 7, AfterEntryID: 100},
+			"after_entry_id=100&category_id=3&feed_id=7",
+		},
+		{
+			"times",
+			EntryQuery{PublishedAfter: day, ChangedBefore: day.Add(time.Hour)},
+			"changed_before=1714525200&published_after=1714521600",
+		},
+		{
+			"paging",
+			EntryQuery{Order: OrderPublishedAt, Direction: DirectionDesc, Limit: 50, Offset: 100},
+			"direction=desc&limit=50&offset=100&order=published_at",
+		},
+		{"globally visible", EntryQuery{GloballyVisible: true}, "globally_visible=true"},
+	} {
+		if got := tc.q.Encode(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
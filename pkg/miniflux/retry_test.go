@@ -0,0 +1,130 @@
+package miniflux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for _, tc := range []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{40, time.Second},
+	} {
+		for i := 0; i < 50; i++ {
+			if d := p.backoff(tc.attempt); d < 0 || d > tc.ceiling {
+				t.Fatalf("backoff(%d) = %v, want within [0, %v]", tc.attempt, d, tc.ceiling)
+			}
+		}
+	}
+	if d := (RetryPolicy{}).backoff(2); d != 0 {
+		t.Errorf("zero policy backoff = %v, want 0", d)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	} {
+		got, ok := parseRetryAfter(tc.value, now)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryableError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"timeout", context.Background(), fmt.Errorf("get: %w", timeoutError{}), true},
+		{"refused", context.Background(), fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"reset", context.Background(), fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"cut short", context.Background(), io.ErrUnexpectedEOF, true},
+		{"missing socket", context.Background(), fmt.Errorf("dial unix: %w", os.ErrNotExist), false},
+		{"pin mismatch", context.Background(), ErrPinMismatch, false},
+		{"other", context.Background(), errors.New("unsupported protocol scheme"), false},
+		{"cancelled", cancelled, syscall.ECONNREFUSED, false},
+	} {
+		if got := isRetryableError(tc.ctx, tc.err); got != tc.want {
+			t.Errorf("%s: isRetryableError = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRetryStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		calls  int
+	}{
+		{http.StatusServiceUnavailable, 3},
+		{http.StatusTooManyRequests, 3},
+		{http.StatusNotFound, 1},
+	} {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(tc.status)
+		}))
+		c := NewClient(srv.URL, "key", false, WithRetryPolicy(RetryPolicy{MaxRetries: 2}))
+		if _, err := c.GetCategories(context.Background()); err == nil {
+			t.Errorf("status %d: expected an error", tc.status)
+		}
+		if calls != tc.calls {
+			t.Errorf("status %d: %d requests, want %d", tc.status, calls, tc.calls)
+		}
+		srv.Close()
+	}
+}
+
+func TestRetryUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	c := NewClient(url, "key", false, WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	if _, err := c.GetCategories(context.Background()); !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("got %v, want connection refused", err)
+	}
+
+	c = NewClient("gopher://example.com", "key", false, WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Hour, MaxDelay: time.Hour}))
+	if _, err := c.GetCategories(context.Background()); err == nil {
+		t.Fatal("expected an unsupported scheme to fail")
+	}
+}
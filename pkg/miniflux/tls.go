@@ -0,0 +1,157 @@
+package miniflux
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrPinMismatch is returned when no certificate presented by the server
+// matches a configured SPKI pin.
+var ErrPinMismatch = errors.New("server certificate does not match any pinned key")
+
+// TLSOptions configures how the client verifies the server and
+// identifies itself to it.
+type TLSOptions struct {
+	InsecureSkipVerify bool
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and key for
+	// mutual TLS.
+	CertFile string
+	KeyFile  string
+	// MinVersion is "1.0", "1.1", "1.2" or "1.3"; empty keeps Go's default.
+	MinVersion string
+	// Pins are SHA-256 hashes of a certificate's SubjectPublicKeyInfo,
+	// base64 (optionally prefixed "sha256//") or hex. When set, the server
+	// chain must contain a matching key.
+	Pins []string
+}
+
+// Config builds a tls.Config from the options, loading any files they
+// reference.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+
+	if o.MinVersion != "" {
+		v, err := parseTLSVersion(o.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = v
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca file %s: no certificates found", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if o.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(o.Pins) > 0 {
+		pins := make([][]byte, 0, len(o.Pins))
+		for _, p := range o.Pins {
+			pin, err := parsePin(p)
+			if err != nil {
+				return nil, err
+			}
+			pins = append(pins, pin)
+		}
+		cfg.VerifyConnection = verifyPins(pins)
+	}
+
+	return cfg, nil
+}
+
+// WithTLS replaces the transport's TLS settings. If the options cannot be
+// loaded every request fails with the error.
+func WithTLS(o TLSOptions) Option {
+	return func(c *Client) {
+		cfg, err := o.Config()
+		if err != nil {
+			c.err = fmt.Errorf("tls: %w", err)
+			return
+		}
+		if tr, ok := c.httpClient.Transport.(*http.Transport); ok {
+			tr.TLSClientConfig = cfg
+		}
+	}
+}
+
+func parseTLSVersion(s string) (uint16, error) {
+	switch s {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q (want 1.0, 1.1, 1.2 or 1.3)", s)
+}
+
+func parsePin(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "sha256//")
+	if h := strings.ReplaceAll(s, ":", ""); len(h) == 2*sha256.Size {
+		if pin, err := hex.DecodeString(h); err == nil {
+			return pin, nil
+		}
+	}
+	if pin, err := base64.StdEncoding.DecodeString(s); err == nil && len(pin) == sha256.Size {
+		return pin, nil
+	}
+	return nil, fmt.Errorf("invalid pin %q: want a SHA-256 hash in base64 or hex", s)
+}
+
+// verifyPins checks the verified chains when the server certificate was
+// verified, and only the leaf otherwise, since any other certificate the
+// server sends is unauthenticated.
+func verifyPins(pins [][]byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		var certs []*x509.Certificate
+		for _, chain := range cs.VerifiedChains {
+			certs = append(certs, chain...)
+		}
+		if len(cs.VerifiedChains) == 0 && len(cs.PeerCertificates) > 0 {
+			certs = cs.PeerCertificates[:1]
+		}
+		for _, cert := range certs {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			for _, pin := range pins {
+				if bytes.Equal(sum[:], pin) {
+					return nil
+				}
+			}
+		}
+		return ErrPinMismatch
+	}
+}
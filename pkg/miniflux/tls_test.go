@@ -0,0 +1,166 @@
+package miniflux
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newCategoriesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+}
+
+func newTestClient(url string, o TLSOptions) *Client {
+	return NewClient(url, "key", false, WithTLS(o), WithRetryPolicy(RetryPolicy{}))
+}
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func spkiPin(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return sum[:]
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	srv := newCategoriesServer(t)
+	srv.StartTLS()
+	defer srv.Close()
+
+	if _, err := newTestClient(srv.URL, TLSOptions{}).GetCategories(context.Background()); err == nil {
+		t.Fatal("expected an untrusted certificate to fail without ca_file")
+	}
+
+	ca := writePEM(t, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	if _, err := newTestClient(srv.URL, TLSOptions{CAFile: ca}).GetCategories(context.Background()); err != nil {
+		t.Fatalf("with ca_file: %v", err)
+	}
+}
+
+func TestTLSPinning(t *testing.T) {
+	srv := newCategoriesServer(t)
+	srv.StartTLS()
+	defer srv.Close()
+	pin := spkiPin(srv.Certificate())
+
+	for _, p := range []string{base64.StdEncoding.EncodeToString(pin), "sha256//" + base64.StdEncoding.EncodeToString(pin), hex.EncodeToString(pin)} {
+		c := newTestClient(srv.URL, TLSOptions{InsecureSkipVerify: true, Pins: []string{p}})
+		if _, err := c.GetCategories(context.Background()); err != nil {
+			t.Errorf("pin %s: %v", p, err)
+		}
+	}
+
+	wrong := sha256.Sum256([]byte("something else"))
+	c := newTestClient(srv.URL, TLSOptions{InsecureSkipVerify: true, Pins: []string{hex.EncodeToString(wrong[:])}})
+	if _, err := c.GetCategories(context.Background()); !errors.Is(err, ErrPinMismatch) {
+		t.Fatalf("wrong pin: got %v, want ErrPinMismatch", err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "goflux test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newCategoriesServer(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+
+	ca := writePEM(t, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	if _, err := newTestClient(srv.URL, TLSOptions{CAFile: ca}).GetCategories(context.Background()); err == nil {
+		t.Fatal("expected the server to reject a client without a certificate")
+	}
+
+	opts := TLSOptions{
+		CAFile:   ca,
+		CertFile: writePEM(t, "client.pem", "CERTIFICATE", der),
+		KeyFile:  writePEM(t, "client.key", "EC PRIVATE KEY", keyDER),
+	}
+	if _, err := newTestClient(srv.URL, opts).GetCategories(context.Background()); err != nil {
+		t.Fatalf("with client certificate: %v", err)
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	srv := newCategoriesServer(t)
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	c := newTestClient(srv.URL, TLSOptions{InsecureSkipVerify: true, MinVersion: "1.3"})
+	if _, err := c.GetCategories(context.Background()); err == nil {
+		t.Fatal("expected a TLS 1.2 server to be rejected with a 1.3 minimum")
+	}
+	c = newTestClient(srv.URL, TLSOptions{InsecureSkipVerify: true, MinVersion: "1.2"})
+	if _, err := c.GetCategories(context.Background()); err != nil {
+		t.Fatalf("with a 1.2 minimum: %v", err)
+	}
+}
+
+func TestTLSOptionsErrors(t *testing.T) {
+	for name, o := range map[string]TLSOptions{
+		"version":     {MinVersion: "2.0"},
+		"pin":         {Pins: []string{"not a hash"}},
+		"cert no key": {CertFile: "client.pem"},
+		"missing ca":  {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+	} {
+		if _, err := o.Config(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if _, err := newTestClient("https://127.0.0.1:1", o).GetCategories(context.Background()); err == nil {
+			t.Errorf("%s: expected requests to fail", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package miniflux
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// WithProxy sends requests through the given proxy instead of the one
+// named by HTTP_PROXY, HTTPS_PROXY and NO_PROXY. http, https, socks5 and
+// socks5h URLs are supported.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) {
+		u, err := url.Parse(proxyURL)
+		if err == nil {
+			switch u.Scheme {
+			case "http", "https", "socks5", "socks5h":
+			default:
+				err = fmt.Errorf("unsupported scheme %q", u.Scheme)
+			}
+		}
+		if err != nil {
+			c.err = fmt.Errorf("proxy url: %w", err)
+			return
+		}
+		if tr, ok := c.httpClient.Transport.(*http.Transport); ok {
+			tr.Proxy = http.ProxyURL(u)
+		}
+	}
+}
+
+// WithUnixSocket connects to the server over a Unix domain socket. The
+// server URL still supplies the Host header and path prefix, and proxies
+// are bypassed.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		tr, ok := c.httpClient.Transport.(*http.Transport)
+		if !ok {
+			return
+		}
+		tr.Proxy = nil
+		tr.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+	}
+}
@@ -16,11 +16,50 @@ func (r ReadStatus) Toggle() ReadStatus {
 	return ReadStatusRead
 }
 
+type Category struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	UserID int    `json:"user_id"`
+}
+
 type Feed struct {
-	ID      int    `json:"id"`
-	Title   string `json:"title"`
-	SiteURL string `json:"site_url"`
-	FeedURL string `json:"feed_url"`
+	ID           int      `json:"id"`
+	Title        string   `json:"title"`
+	SiteURL      string   `json:"site_url"`
+	FeedURL      string   `json:"feed_url"`
+	Category     Category `json:"category"`
+	Crawler      bool     `json:"crawler"`
+	ScraperRules string   `json:"scraper_rules"`
+	RewriteRules string   `json:"rewrite_rules"`
+}
+
+// Subscription is a feed found by Discover.
+type Subscription struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+}
+
+type FeedCreationRequest struct {
+	FeedURL      string `json:"feed_url"`
+	CategoryID   int    `json:"category_id"`
+	Crawler      bool   `json:"crawler,omitempty"`
+	ScraperRules string `json:"scraper_rules,omitempty"`
+}
+
+// FeedModificationRequest changes only the fields that are set.
+type FeedModificationRequest struct {
+	Title        *string `json:"title,omitempty"`
+	CategoryID   *int    `json:"category_id,omitempty"`
+	Crawler      *bool   `json:"crawler,omitempty"`
+	ScraperRules *string `json:"scraper_rules,omitempty"`
+	RewriteRules *string `json:"rewrite_rules,omitempty"`
+}
+
+// FeedCounters maps feed IDs to their read and unread entry counts.
+type FeedCounters struct {
+	Reads   map[int]int `json:"reads"`
+	Unreads map[int]int `json:"unreads"`
 }
 
 type FeedEntry struct {
@@ -33,6 +72,7 @@ type FeedEntry struct {
 	Status      ReadStatus `json:"status"`
 	Starred     bool       `json:"starred"`
 	PublishedAt time.Time  `json:"published_at"`
+	ChangedAt   time.Time  `json:"changed_at"`
 	// OriginalContent is optional
 	OriginalContent string `json:"original_content,omitempty"`
 }
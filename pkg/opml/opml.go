@@ -0,0 +1,170 @@
+// Package opml reads and writes OPML subscription lists and compares
+// them without talking to a server.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Subscription is one feed in an OPML file. Category is the title of
+// the enclosing outline, or empty for feeds at the top level.
+type Subscription struct {
+	Title    string
+	FeedURL  string
+	SiteURL  string
+	Category string
+}
+
+type document struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Head    head      `xml:"head"`
+	Body    []outline `xml:"body>outline"`
+}
+
+type head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []outline `xml:"outline"`
+}
+
+func (o outline) title() string {
+	if o.Title != "" {
+		return o.Title
+	}
+	return o.Text
+}
+
+// Parse reads the feeds in an OPML document. Nested folders are
+// flattened; a feed's category is its innermost folder.
+func Parse(r io.Reader) ([]Subscription, error) {
+	var doc document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse OPML: %w", err)
+	}
+	var subs []Subscription
+	var walk func(outlines []outline, category string)
+	walk = func(outlines []outline, category string) {
+		for _, o := range outlines {
+			if o.XMLURL == "" {
+				walk(o.Outlines, o.title())
+				continue
+			}
+			subs = append(subs, Subscription{
+				Title:    o.title(),
+				FeedURL:  strings.TrimSpace(o.XMLURL),
+				SiteURL:  strings.TrimSpace(o.HTMLURL),
+				Category: category,
+			})
+		}
+	}
+	walk(doc.Body, "")
+	return subs, nil
+}
+
+// Write encodes subs as an OPML 2.0 document with one folder per
+// category, both sorted by title.
+func Write(w io.Writer, title string, subs []Subscription) error {
+	byCategory := make(map[string][]outline)
+	for _, sub := range subs {
+		byCategory[sub.Category] = append(byCategory[sub.Category], outline{
+			Text:    sub.Title,
+			Title:   sub.Title,
+			Type:    "rss",
+			XMLURL:  sub.FeedURL,
+			HTMLURL: sub.SiteURL,
+		})
+	}
+	categories := make([]string, 0, len(byCategory))
+	for category, feeds := range byCategory {
+		categories = append(categories, category)
+		sort.Slice(feeds, func(i, j int) bool {
+			return strings.ToLower(feeds[i].Text) < strings.ToLower(feeds[j].Text)
+		})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return strings.ToLower(categories[i]) < strings.ToLower(categories[j])
+	})
+
+	doc := document{
+		Version: "2.0",
+		Head:    head{Title: title, DateCreated: time.Now().Format(time.RFC1123Z)},
+	}
+	for _, category := range categories {
+		if category == "" {
+			doc.Body = append(doc.Body, byCategory[category]...)
+			continue
+		}
+		doc.Body = append(doc.Body, outline{Text: category, Title: category, Outlines: byCategory[category]})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Diff compares the feeds in a file with those on a server.
+type Diff struct {
+	// Added are in the file but not on the server, so an import would
+	// subscribe to them.
+	Added []Subscription
+	// Missing are on the server but not in the file.
+	Missing []Subscription
+}
+
+// Compare matches feeds by URL, ignoring differences in scheme and host
+// case and a trailing slash.
+func Compare(file, server []Subscription) Diff {
+	onServer := make(map[string]bool, len(server))
+	for _, sub := range server {
+		onServer[normalizeURL(sub.FeedURL)] = true
+	}
+	inFile := make(map[string]bool, len(file))
+	var diff Diff
+	for _, sub := range file {
+		key := normalizeURL(sub.FeedURL)
+		if !onServer[key] && !inFile[key] {
+			diff.Added = append(diff.Added, sub)
+		}
+		inFile[key] = true
+	}
+	for _, sub := range server {
+		if !inFile[normalizeURL(sub.FeedURL)] {
+			diff.Missing = append(diff.Missing, sub)
+		}
+	}
+	return diff
+}
+
+func normalizeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
@@ -0,0 +1,80 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Top" xmlUrl=" https://top.example/feed " htmlUrl="https://top.example"/>
+    <outline text="Tech" title="Technology">
+      <outline text="Go" xmlUrl="https://go.dev/blog/feed.atom"/>
+      <outline text="Nested">
+        <outline text="Deep" title="Deep title" xmlUrl="https://deep.example/rss"/>
+      </outline>
+    </outline>
+  </body>
+</opml>`
+
+func TestParse(t *testing.T) {
+	subs, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Subscription{
+		{Title: "Top", FeedURL: "https://top.example/feed", SiteURL: "https://top.example"},
+		{Title: "Go", FeedURL: "https://go.dev/blog/feed.atom", Category: "Technology"},
+		{Title: "Deep title", FeedURL: "https://deep.example/rss", Category: "Nested"},
+	}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("got %+v\nwant %+v", subs, want)
+	}
+
+	if _, err := Parse(strings.NewReader("not xml")); err == nil {
+		t.Error("expected an error for a file that is not OPML")
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	subs := []Subscription{
+		{Title: "b", FeedURL: "https://b.example/feed", Category: "News"},
+		{Title: "A", FeedURL: "https://a.example/feed", SiteURL: "https://a.example", Category: "News"},
+		{Title: "Loose", FeedURL: "https://loose.example/feed"},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, "test", subs); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Subscription{subs[2], subs[1], subs[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	file := []Subscription{
+		{Title: "same", FeedURL: "HTTPS://Example.com/feed/"},
+		{Title: "new", FeedURL: "https://new.example/rss"},
+		{Title: "new again", FeedURL: "https://new.example/rss/"},
+	}
+	server := []Subscription{
+		{Title: "same", FeedURL: "https://example.com/feed"},
+		{Title: "gone", FeedURL: "https://gone.example/rss"},
+	}
+	diff := Compare(file, server)
+	if len(diff.Added) != 1 || diff.Added[0].Title != "new" {
+		t.Errorf("Added = %+v, want only new", diff.Added)
+	}
+	if len(diff.Missing) != 1 || diff.Missing[0].Title != "gone" {
+		t.Errorf("Missing = %+v, want only gone", diff.Missing)
+	}
+}
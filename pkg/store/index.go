@@ -0,0 +1,153 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jaytaylor/html2text"
+	"github.com/slatkin/goflux/pkg/miniflux"
+	bolt "go.etcd.io/bbolt"
+)
+
+// indexRecord is the part of an entry that queries filter and sort on.
+// The index bucket holds one per entry, packed in a few bytes, so
+// listing a page only decodes the entries on it.
+type indexRecord struct {
+	ID          int
+	FeedID      int
+	CategoryID  int
+	Status      miniflux.ReadStatus
+	Starred     bool
+	PublishedAt time.Time
+	ChangedAt   time.Time
+}
+
+// indexSize is the encoded size of a record without its status.
+const indexSize = 8 + 8 + 12 + 12 + 1
+
+func newIndexRecord(entry miniflux.FeedEntry) indexRecord {
+	return indexRecord{
+		ID:          entry.ID,
+		FeedID:      entry.FeedID,
+		CategoryID:  entry.Feed.Category.ID,
+		Status:      entry.Status,
+		Starred:     entry.Starred,
+		PublishedAt: entry.PublishedAt,
+		ChangedAt:   entry.ChangedAt,
+	}
+}
+
+func (r indexRecord) encode() []byte {
+	b := make([]byte, indexSize, indexSize+len(r.Status))
+	binary.BigEndian.PutUint64(b[0:], uint64(r.FeedID))
+	binary.BigEndian.PutUint64(b[8:], uint64(r.CategoryID))
+	putTime(b[16:], r.PublishedAt)
+	putTime(b[28:], r.ChangedAt)
+	if r.Starred {
+		b[40] = 1
+	}
+	return append(b, r.Status...)
+}
+
+func decodeIndex(key, v []byte) (indexRecord, error) {
+	if len(v) < indexSize {
+		return indexRecord{}, errors.New("corrupt entry index")
+	}
+	return indexRecord{
+		ID:          int(binary.BigEndian.Uint64(key)),
+		FeedID:      int(binary.BigEndian.Uint64(v[0:])),
+		CategoryID:  int(binary.BigEndian.Uint64(v[8:])),
+		PublishedAt: readTime(v[16:]),
+		ChangedAt:   readTime(v[28:]),
+		Starred:     v[40] == 1,
+		Status:      miniflux.ReadStatus(v[indexSize:]),
+	}, nil
+}
+
+func putTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint64(b, uint64(t.Unix()))
+	binary.BigEndian.PutUint32(b[8:], uint32(t.Nanosecond()))
+}
+
+func readTime(b []byte) time.Time {
+	t := time.Unix(int64(binary.BigEndian.Uint64(b)), int64(binary.BigEndian.Uint32(b[8:])))
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t
+}
+
+// putEntry stores an entry's metadata, without content, and its index
+// record.
+func putEntry(tx *bolt.Tx, entry miniflux.FeedEntry) error {
+	key := itob(entry.ID)
+	if err := putJSON(tx.Bucket(bucketEntries), key, entry); err != nil {
+		return err
+	}
+	return tx.Bucket(bucketIndex).Put(key, newIndexRecord(entry).encode())
+}
+
+func deleteEntry(tx *bolt.Tx, key []byte) error {
+	for _, name := range [][]byte{bucketEntries, bucketContent, bucketIndex, bucketText} {
+		if err := tx.Bucket(name).Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putText stores the lowercase title and content text that searches
+// match against, so a search does not convert every entry's HTML.
+func putText(tx *bolt.Tx, id int, title string, c entryContent) error {
+	text, err := html2text.FromString(c.Content+"\n"+c.OriginalContent, html2text.Options{OmitLinks: true})
+	if err != nil {
+		text = ""
+	}
+	return tx.Bucket(bucketText).Put(itob(id), []byte(strings.ToLower(title+"\n"+text)))
+}
+
+// rebuildIndex fills the index and text buckets from the stored entries,
+// for a cache written before they existed.
+func rebuildIndex(tx *bolt.Tx) error {
+	for _, name := range [][]byte{bucketIndex, bucketText} {
+		if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		if _, err := tx.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	index, contents := tx.Bucket(bucketIndex), tx.Bucket(bucketContent)
+	return tx.Bucket(bucketEntries).ForEach(func(k, v []byte) error {
+		var entry miniflux.FeedEntry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if err := index.Put(k, newIndexRecord(entry).encode()); err != nil {
+			return err
+		}
+		var c entryContent
+		if v := contents.Get(k); v != nil {
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+		}
+		return putText(tx, entry.ID, entry.Title, c)
+	})
+}
+
+// forEachIndex calls fn with the index record of every stored entry.
+func (s *Store) forEachIndex(fn func(r indexRecord) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketIndex).ForEach(func(k, v []byte) error {
+			r, err := decodeIndex(k, v)
+			if err != nil {
+				return err
+			}
+			return fn(r)
+		})
+	})
+}
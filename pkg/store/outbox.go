@@ -0,0 +1,310 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+	bolt "go.etcd.io/bbolt"
+)
+
+type ActionKind string
+
+const (
+	ActionRead   ActionKind = "read"
+	ActionUnread ActionKind = "unread"
+	ActionStar   ActionKind = "star"
+	ActionUnstar ActionKind = "unstar"
+	ActionSave   ActionKind = "save"
+)
+
+// Action is an entry change made locally that the server has not
+// confirmed yet.
+type Action struct {
+	Seq     uint64     `json:"seq"`
+	Kind    ActionKind `json:"kind"`
+	EntryID int        `json:"entry_id"`
+	Queued  time.Time  `json:"queued"`
+}
+
+func (a Action) isStatus() bool {
+	return a.Kind == ActionRead || a.Kind == ActionUnread
+}
+
+func (a Action) isStar() bool {
+	return a.Kind == ActionStar || a.Kind == ActionUnstar
+}
+
+// Outbox queues entry actions until they reach the server. Actions are
+// kept in the store's database so they survive restarts, or in memory
+// for an outbox made with NewOutbox.
+type Outbox struct {
+	mu   sync.Mutex
+	db   *bolt.DB
+	mem  []Action
+	next uint64
+	// replaying serializes Replay so no action is sent twice.
+	replaying sync.Mutex
+}
+
+// NewOutbox returns an outbox that lives only as long as the process.
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Outbox returns the store's durable outbox.
+func (s *Store) Outbox() *Outbox {
+	return s.outbox
+}
+
+// Add queues an action, coalescing it with those already queued for the
+// same entry: only the last read status and star changes are kept and a
+// repeated save is dropped.
+func (o *Outbox) Add(kind ActionKind, entryID int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	a := Action{Kind: kind, EntryID: entryID, Queued: time.Now()}
+	if o.db == nil {
+		o.next++
+		a.Seq = o.next
+		o.mem = coalesce(o.mem, a)
+		return nil
+	}
+	return o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketOutbox)
+		old, err := readActions(b)
+		if err != nil {
+			return err
+		}
+		if a.Seq, err = b.NextSequence(); err != nil {
+			return err
+		}
+		return writeActions(b, old, coalesce(old, a))
+	})
+}
+
+// Pending lists the queued actions, oldest first.
+func (o *Outbox) Pending() ([]Action, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.db == nil {
+		return append([]Action(nil), o.mem...), nil
+	}
+	var actions []Action
+	err := o.db.View(func(tx *bolt.Tx) error {
+		var err error
+		actions, err = readActions(tx.Bucket(bucketOutbox))
+		return err
+	})
+	return actions, err
+}
+
+// remove drops actions that were sent or given up on.
+func (o *Outbox) remove(done []Action) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	drop := make(map[uint64]bool, len(done))
+	for _, a := range done {
+		drop[a.Seq] = true
+	}
+	if o.db == nil {
+		kept := o.mem[:0]
+		for _, a := range o.mem {
+			if !drop[a.Seq] {
+				kept = append(kept, a)
+			}
+		}
+		o.mem = kept
+		return nil
+	}
+	return o.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketOutbox)
+		for seq := range drop {
+			if err := b.Delete(seqKey(seq)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func coalesce(queued []Action, a Action) []Action {
+	out := make([]Action, 0, len(queued)+1)
+	add := true
+	for _, q := range queued {
+		switch {
+		case q.EntryID != a.EntryID:
+		case q.isStatus() && a.isStatus():
+			continue
+		case q.isStar() && a.isStar():
+			continue
+		case q.Kind == ActionSave && a.Kind == ActionSave:
+			add = false
+		}
+		out = append(out, q)
+	}
+	if add {
+		out = append(out, a)
+	}
+	return out
+}
+
+func seqKey(seq uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, seq)
+	return b
+}
+
+func readActions(b *bolt.Bucket) ([]Action, error) {
+	var actions []Action
+	err := b.ForEach(func(_, v []byte) error {
+		var a Action
+		if err := json.Unmarshal(v, &a); err != nil {
+			return err
+		}
+		actions = append(actions, a)
+		return nil
+	})
+	return actions, err
+}
+
+func writeActions(b *bolt.Bucket, old, actions []Action) error {
+	for _, a := range old {
+		if err := b.Delete(seqKey(a.Seq)); err != nil {
+			return err
+		}
+	}
+	for _, a := range actions {
+		if err := putJSON(b, seqKey(a.Seq), a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FailedAction is an action the server rejected for good.
+type FailedAction struct {
+	Action
+	Err error
+}
+
+// ReplayResult reports what a Replay did.
+type ReplayResult struct {
+	Sent   int
+	Failed []FailedAction
+	// Remaining counts actions still queued because the server could not
+	// be reached.
+	Remaining int
+}
+
+// Replay sends the queued actions in order, batching consecutive read
+// status changes into one request per status. It stops at the first
+// error that may go away by itself, such as a network failure, and
+// returns it with the actions still queued. Actions the server rejects
+// outright are removed and reported in Failed so they can be undone
+// locally.
+func (o *Outbox) Replay(ctx context.Context, c *miniflux.Client) (ReplayResult, error) {
+	o.replaying.Lock()
+	defer o.replaying.Unlock()
+
+	var result ReplayResult
+	actions, err := o.Pending()
+	if err != nil {
+		return result, err
+	}
+
+	for i := 0; i < len(actions); {
+		j := i + 1
+		if actions[i].isStatus() {
+			for j < len(actions) && actions[j].Kind == actions[i].Kind {
+				j++
+			}
+		}
+		run := actions[i:j]
+		i = j
+
+		err := send(ctx, c, run)
+		if err != nil && isPermanent(err) && len(run) > 1 {
+			// One bad entry fails the whole batch, so find out which
+			for _, a := range run {
+				if err = o.settle([]Action{a}, send(ctx, c, []Action{a}), &result); err != nil {
+					break
+				}
+			}
+		} else {
+			err = o.settle(run, err, &result)
+		}
+		if err != nil {
+			if pending, perr := o.Pending(); perr == nil {
+				result.Remaining = len(pending)
+			}
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// settle takes run off the queue once it was sent or rejected for good.
+// A transient send error is returned and run stays queued.
+func (o *Outbox) settle(run []Action, err error, result *ReplayResult) error {
+	if err != nil && !isPermanent(err) {
+		return err
+	}
+	if err == nil {
+		result.Sent += len(run)
+	}
+	for _, a := range run {
+		if err != nil {
+			result.Failed = append(result.Failed, FailedAction{Action: a, Err: err})
+		}
+	}
+	return o.remove(run)
+}
+
+func send(ctx context.Context, c *miniflux.Client, run []Action) error {
+	switch a := run[0]; a.Kind {
+	case ActionRead, ActionUnread:
+		ids := make([]int, len(run))
+		for i, r := range run {
+			ids[i] = r.EntryID
+		}
+		status := miniflux.ReadStatusRead
+		if a.Kind == ActionUnread {
+			status = miniflux.ReadStatusUnread
+		}
+		return c.ChangeEntryReadStatus(ctx, ids, status)
+	case ActionStar, ActionUnstar:
+		// The API can only toggle the star, so check the entry first
+		entry, err := c.GetEntry(ctx, a.EntryID)
+		if err != nil || entry.Starred == (a.Kind == ActionStar) {
+			return err
+		}
+		return c.ToggleStarred(ctx, a.EntryID)
+	case ActionSave:
+		return c.SaveEntry(ctx, a.EntryID)
+	}
+	return nil
+}
+
+// isPermanent reports whether the server rejected a request in a way
+// that sending it again will not fix. Auth failures are not permanent:
+// the actions stay queued until the credentials are fixed.
+func isPermanent(err error) bool {
+	var apiErr *miniflux.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500
+}
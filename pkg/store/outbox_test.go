@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+type queued struct {
+	kind ActionKind
+	id   int
+}
+
+func addAll(t *testing.T, o *Outbox, actions []queued) {
+	t.Helper()
+	for _, a := range actions {
+		if err := o.Add(a.kind, a.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func pendingList(t *testing.T, o *Outbox) []queued {
+	t.Helper()
+	pending, err := o.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []queued
+	for _, a := range pending {
+		got = append(got, queued{a.Kind, a.EntryID})
+	}
+	return got
+}
+
+func TestOutboxCoalesce(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		add  []queued
+		want []queued
+	}{
+		{"read unread read", []queued{{ActionRead, 1}, {ActionUnread, 1}, {ActionRead, 1}}, []queued{{ActionRead, 1}}},
+		{"other entries", []queued{{ActionRead, 1}, {ActionRead, 2}}, []queued{{ActionRead, 1}, {ActionRead, 2}}},
+		{"star unstar", []queued{{ActionStar, 1}, {ActionUnstar, 1}}, []queued{{ActionUnstar, 1}}},
+		{"status and star", []queued{{ActionRead, 1}, {ActionStar, 1}, {ActionUnread, 1}}, []queued{{ActionStar, 1}, {ActionUnread, 1}}},
+		{"later status moves last", []queued{{ActionRead, 1}, {ActionRead, 2}, {ActionUnread, 1}}, []queued{{ActionRead, 2}, {ActionUnread, 1}}},
+		{"repeated save", []queued{{ActionSave, 1}, {ActionSave, 1}}, []queued{{ActionSave, 1}}},
+	} {
+		for name, o := range map[string]*Outbox{"memory": NewOutbox(), "durable": openTestStore(t).Outbox()} {
+			addAll(t, o, tc.add)
+			if got := pendingList(t, o); !slices.Equal(got, tc.want) {
+				t.Errorf("%s (%s): got %v, want %v", tc.name, name, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestOutboxReplay(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		setup   func(f *fakeServer)
+		add     []queued
+		want    []string
+		sent    int
+		failed  []int
+		wantErr bool
+		starred bool
+	}{
+		{
+			name: "batches status runs",
+			add:  []queued{{ActionRead, 1}, {ActionRead, 2}, {ActionUnread, 3}, {ActionRead, 4}},
+			want: []string{
+				"PUT /v1/entries read [1 2]",
+				"PUT /v1/entries unread [3]",
+				"PUT /v1/entries read [4]",
+			},
+			sent: 4,
+		},
+		{
+			name:  "splits a rejected batch",
+			setup: func(f *fakeServer) { f.reject[2] = true },
+			add:   []queued{{ActionRead, 1}, {ActionRead, 2}, {ActionRead, 3}},
+			want: []string{
+				"PUT /v1/entries read [1 2 3]",
+				"PUT /v1/entries read [1]",
+				"PUT /v1/entries read [2]",
+				"PUT /v1/entries read [3]",
+			},
+			sent:   2,
+			failed: []int{2},
+		},
+		{
+			name:    "keeps actions while the server is down",
+			setup:   func(f *fakeServer) { f.down = true },
+			add:     []queued{{ActionRead, 1}, {ActionStar, 2}},
+			wantErr: true,
+		},
+		{
+			name: "star already set",
+			setup: func(f *fakeServer) {
+				entry := f.entries[1]
+				entry.Starred = true
+				f.entries[1] = entry
+			},
+			add:     []queued{{ActionStar, 1}},
+			sent:    1,
+			starred: true,
+		},
+		{
+			name:    "star toggles",
+			add:     []queued{{ActionStar, 1}},
+			want:    []string{"PUT /v1/entries/1/bookmark"},
+			sent:    1,
+			starred: true,
+		},
+		{
+			name: "star and unstar cancel out",
+			add:  []queued{{ActionStar, 1}, {ActionUnstar, 1}},
+			sent: 1,
+		},
+		{
+			name: "save",
+			add:  []queued{{ActionSave, 1}},
+			want: []string{"POST /v1/entries/1/save"},
+			sent: 1,
+		},
+	} {
+		var entries []miniflux.FeedEntry
+		for id := 1; id <= 4; id++ {
+			entries = append(entries, testEntry(id, 1, miniflux.ReadStatusUnread, time.Now()))
+		}
+		f, client := newFakeServer(t, entries...)
+		if tc.setup != nil {
+			tc.setup(f)
+		}
+		o := openTestStore(t).Outbox()
+		addAll(t, o, tc.add)
+
+		result, err := o.Replay(context.Background(), client)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: err = %v", tc.name, err)
+		}
+		if !slices.Equal(f.sent, tc.want) {
+			t.Errorf("%s: sent %q, want %q", tc.name, f.sent, tc.want)
+		}
+		var failed []int
+		for _, a := range result.Failed {
+			failed = append(failed, a.EntryID)
+		}
+		if result.Sent != tc.sent || !slices.Equal(failed, tc.failed) {
+			t.Errorf("%s: sent %d, failed %v, want %d, %v", tc.name, result.Sent, failed, tc.sent, tc.failed)
+		}
+
+		remaining := 0
+		if tc.wantErr {
+			remaining = len(tc.add)
+		}
+		if n := len(pendingList(t, o)); result.Remaining != remaining || n != remaining {
+			t.Errorf("%s: Remaining = %d with %d queued, want %d", tc.name, result.Remaining, n, remaining)
+		}
+		if got := f.entry(1).Starred; got != tc.starred {
+			t.Errorf("%s: entry 1 starred = %v on the server, want %v", tc.name, got, tc.starred)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Entries answers an entries query from the store, returning one page
+// and the total number of matches like Client.GetEntries. Status, starred,
+// feed, category, date and search filters are supported; search matches
+// entries whose title or content text contains every word. It only sees
+// what is cached, so it stands in for the server's search when offline.
+func (s *Store) Entries(q miniflux.EntryQuery) ([]miniflux.FeedEntry, int, error) {
+	words := strings.Fields(strings.ToLower(q.Search))
+
+	var page []miniflux.FeedEntry
+	total := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		texts := tx.Bucket(bucketText)
+		var matched []indexRecord
+		err := tx.Bucket(bucketIndex).ForEach(func(k, v []byte) error {
+			r, err := decodeIndex(k, v)
+			if err != nil {
+				return err
+			}
+			if !r.matches(q) {
+				return nil
+			}
+			if len(words) > 0 && !containsWords(texts.Get(k), words) {
+				return nil
+			}
+			matched = append(matched, r)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		sortRecords(matched, q.Order, q.Direction)
+		total = len(matched)
+		start := min(q.Offset, total)
+		end := total
+		if q.Limit > 0 {
+			end = min(start+q.Limit, total)
+		}
+		entries := tx.Bucket(bucketEntries)
+		page = make([]miniflux.FeedEntry, 0, end-start)
+		for _, r := range matched[start:end] {
+			var entry miniflux.FeedEntry
+			if err := json.Unmarshal(entries.Get(itob(r.ID)), &entry); err != nil {
+				return err
+			}
+			if err := attachContent(tx, &entry); err != nil {
+				return err
+			}
+			page = append(page, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return page, total, nil
+}
+
+// containsWords reports whether every word appears in an entry's search
+// text, which putText stored lowercase and without markup.
+func containsWords(text []byte, words []string) bool {
+	for _, w := range words {
+		if !bytes.Contains(text, []byte(w)) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnreadCount is the number of stored unread entries.
+func (s *Store) UnreadCount() (int, error) {
+	n := 0
+	err := s.forEachIndex(func(r indexRecord) error {
+		if r.Status == miniflux.ReadStatusUnread {
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+func (r indexRecord) matches(q miniflux.EntryQuery) bool {
+	switch {
+	case len(q.Status) > 0 && !slices.Contains(q.Status, r.Status):
+		return false
+	case q.Starred != nil && r.Starred != *q.Starred:
+		return false
+	case q.FeedID != 0 && r.FeedID != q.FeedID:
+		return false
+	case q.CategoryID != 0 && r.CategoryID != q.CategoryID:
+		return false
+	case !inRange(r.PublishedAt, q.PublishedAfter, q.PublishedBefore):
+		return false
+	case !inRange(r.ChangedAt, q.ChangedAfter, q.ChangedBefore):
+		return false
+	}
+	return true
+}
+
+func inRange(t, after, before time.Time) bool {
+	return (after.IsZero() || t.After(after)) && (before.IsZero() || t.Before(before))
+}
+
+// sortRecords orders entries by published or changed time, falling back
+// to the entry ID, in the direction Miniflux would use.
+func sortRecords(records []indexRecord, order miniflux.EntryOrder, dir miniflux.SortDirection) {
+	key := func(r indexRecord) time.Time { return r.PublishedAt }
+	if order == miniflux.OrderChangedAt {
+		key = func(r indexRecord) time.Time { return r.ChangedAt }
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if dir == miniflux.DirectionDesc {
+			a, b = b, a
+		}
+		if order != miniflux.OrderID && !key(a).Equal(key(b)) {
+			return key(a).Before(key(b))
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,369 @@
+// Package store keeps a local copy of entries, feeds and categories so
+// goflux can start without waiting on the server and read offline.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketEntries    = []byte("entries")
+	bucketContent    = []byte("content")
+	bucketFeeds      = []byte("feeds")
+	bucketCategories = []byte("categories")
+	bucketMeta       = []byte("meta")
+	bucketOutbox     = []byte("outbox")
+	bucketIndex      = []byte("index")
+	bucketText       = []byte("text")
+
+	allBuckets = [][]byte{bucketEntries, bucketContent, bucketFeeds, bucketCategories, bucketMeta, bucketOutbox, bucketIndex, bucketText}
+
+	keyServer  = []byte("server")
+	keyCursor  = []byte("cursor")
+	keyVersion = []byte("version")
+)
+
+// version changes when the layout of the buckets does; an older store's
+// index is rebuilt when it is opened.
+const version = "2"
+
+// Store is a bbolt database holding one server's data. Entry metadata
+// and content are kept in separate buckets so listing entries does not
+// decode every article body, and a packed index lets queries skip
+// decoding the metadata of entries they do not return.
+type Store struct {
+	db     *bolt.DB
+	outbox *Outbox
+}
+
+// entryContent is what the content bucket holds for each entry.
+type entryContent struct {
+	Content         string `json:"content"`
+	OriginalContent string `json:"original_content,omitempty"`
+}
+
+// Open opens or creates the store at path. A store last used with a
+// different server is emptied first so data from two servers never mixes.
+func Open(path, serverURL string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open cache %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if meta := tx.Bucket(bucketMeta); meta != nil && string(meta.Get(keyServer)) != serverURL {
+			for _, name := range allBuckets {
+				if err := tx.DeleteBucket(name); err != nil && err != bolt.ErrBucketNotFound {
+					return err
+				}
+			}
+		}
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		meta := tx.Bucket(bucketMeta)
+		if string(meta.Get(keyVersion)) != version {
+			if err := rebuildIndex(tx); err != nil {
+				return err
+			}
+			if err := meta.Put(keyVersion, []byte(version)); err != nil {
+				return err
+			}
+		}
+		return meta.Put(keyServer, []byte(serverURL))
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db, outbox: &Outbox{db: db}}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func itob(id int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(id))
+	return b
+}
+
+// Cursor is the changed_at time the next sync continues from. It is zero
+// until the first sync completes.
+func (s *Store) Cursor() (time.Time, error) {
+	var t time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucketMeta).Get(keyCursor); v != nil {
+			return t.UnmarshalText(v)
+		}
+		return nil
+	})
+	return t, err
+}
+
+func (s *Store) setCursor(t time.Time) error {
+	v, err := t.MarshalText()
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMeta).Put(keyCursor, v)
+	})
+}
+
+// PutEntries adds or replaces entries. Original content fetched earlier
+// is kept unless the new copy carries its own.
+func (s *Store) PutEntries(entries []miniflux.FeedEntry) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		contents := tx.Bucket(bucketContent)
+		for _, entry := range entries {
+			key := itob(entry.ID)
+			c := entryContent{Content: entry.Content, OriginalContent: entry.OriginalContent}
+			if c.OriginalContent == "" {
+				var old entryContent
+				if v := contents.Get(key); v != nil && json.Unmarshal(v, &old) == nil {
+					c.OriginalContent = old.OriginalContent
+				}
+			}
+
+			entry.Content, entry.OriginalContent = "", ""
+			if err := putEntry(tx, entry); err != nil {
+				return err
+			}
+			if err := putJSON(contents, key, c); err != nil {
+				return err
+			}
+			if err := putText(tx, entry.ID, entry.Title, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Entry returns a single entry with its content, or false if it is not
+// stored.
+func (s *Store) Entry(id int) (miniflux.FeedEntry, bool, error) {
+	var entry miniflux.FeedEntry
+	found := false
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketEntries).Get(itob(id))
+		if v == nil {
+			return nil
+		}
+		found = true
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		return attachContent(tx, &entry)
+	})
+	return entry, found, err
+}
+
+func attachContent(tx *bolt.Tx, entry *miniflux.FeedEntry) error {
+	v := tx.Bucket(bucketContent).Get(itob(entry.ID))
+	if v == nil {
+		return nil
+	}
+	var c entryContent
+	if err := json.Unmarshal(v, &c); err != nil {
+		return err
+	}
+	entry.Content, entry.OriginalContent = c.Content, c.OriginalContent
+	return nil
+}
+
+// SetStatus records a read status change made by this client.
+func (s *Store) SetStatus(ids []int, status miniflux.ReadStatus) error {
+	return s.updateEntries(ids, func(e *miniflux.FeedEntry) {
+		e.Status = status
+	})
+}
+
+// SetStarred records a bookmark change made by this client.
+func (s *Store) SetStarred(id int, starred bool) error {
+	return s.updateEntries([]int{id}, func(e *miniflux.FeedEntry) {
+		e.Starred = starred
+	})
+}
+
+func (s *Store) updateEntries(ids []int, update func(*miniflux.FeedEntry)) error {
+	now := time.Now()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		for _, id := range ids {
+			v := b.Get(itob(id))
+			if v == nil {
+				continue
+			}
+			var entry miniflux.FeedEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			update(&entry)
+			entry.ChangedAt = now
+			if err := putEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetOriginalContent stores content fetched from an entry's website.
+func (s *Store) SetOriginalContent(id int, content string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketContent)
+		key := itob(id)
+		var c entryContent
+		if v := b.Get(key); v != nil {
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+		}
+		c.OriginalContent = content
+		if err := putJSON(b, key, c); err != nil {
+			return err
+		}
+		var entry miniflux.FeedEntry
+		if v := tx.Bucket(bucketEntries).Get(key); v != nil {
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+		}
+		return putText(tx, id, entry.Title, c)
+	})
+}
+
+// PutFeeds replaces the stored feeds. Entries carry a copy of their feed,
+// so those of renamed or moved feeds are updated to match.
+func (s *Store) PutFeeds(feeds []miniflux.Feed) error {
+	err := s.replace(bucketFeeds, len(feeds), func(i int) (int, any) {
+		return feeds[i].ID, feeds[i]
+	})
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]miniflux.Feed, len(feeds))
+	for _, feed := range feeds {
+		byID[feed.ID] = feed
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		var changed []miniflux.FeedEntry
+		err := b.ForEach(func(_, v []byte) error {
+			var entry miniflux.FeedEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			feed, ok := byID[entry.FeedID]
+			if ok && (entry.Feed.Title != feed.Title || entry.Feed.Category != feed.Category) {
+				entry.Feed = feed
+				changed = append(changed, entry)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, entry := range changed {
+			if err := putEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutCategories replaces the stored categories.
+func (s *Store) PutCategories(categories []miniflux.Category) error {
+	return s.replace(bucketCategories, len(categories), func(i int) (int, any) {
+		return categories[i].ID, categories[i]
+	})
+}
+
+func (s *Store) replace(name []byte, n int, item func(int) (int, any)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+		b, err := tx.CreateBucket(name)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			id, v := item(i)
+			if err := putJSON(b, itob(id), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) Feeds() ([]miniflux.Feed, error) {
+	var feeds []miniflux.Feed
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketFeeds).ForEach(func(_, v []byte) error {
+			var feed miniflux.Feed
+			if err := json.Unmarshal(v, &feed); err != nil {
+				return err
+			}
+			feeds = append(feeds, feed)
+			return nil
+		})
+	})
+	return feeds, err
+}
+
+func (s *Store) Categories() ([]miniflux.Category, error) {
+	var categories []miniflux.Category
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketCategories).ForEach(func(_, v []byte) error {
+			var category miniflux.Category
+			if err := json.Unmarshal(v, &category); err != nil {
+				return err
+			}
+			categories = append(categories, category)
+			return nil
+		})
+	})
+	return categories, err
+}
+
+// Counters counts the stored read and unread entries per feed.
+func (s *Store) Counters() (miniflux.FeedCounters, error) {
+	counters := miniflux.FeedCounters{Reads: map[int]int{}, Unreads: map[int]int{}}
+	err := s.forEachIndex(func(r indexRecord) error {
+		if r.Status == miniflux.ReadStatusUnread {
+			counters.Unreads[r.FeedID]++
+		} else {
+			counters.Reads[r.FeedID]++
+		}
+		return nil
+	})
+	return counters, err
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
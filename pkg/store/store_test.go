@@ -0,0 +1,271 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// fakeServer is a small in-memory Miniflux that records the changes
+// clients send it.
+type fakeServer struct {
+	mu         sync.Mutex
+	entries    map[int]miniflux.FeedEntry
+	feeds      []miniflux.Feed
+	categories []miniflux.Category
+	// reject lists entry IDs the server refuses with 400.
+	reject map[int]bool
+	// down makes every request fail with 503.
+	down bool
+	// sent records each change as "PUT /v1/entries read [1 2]" and the
+	// like; queries records the entry list queries.
+	sent    []string
+	queries []string
+}
+
+func newFakeServer(t *testing.T, entries ...miniflux.FeedEntry) (*fakeServer, *miniflux.Client) {
+	t.Helper()
+	f := &fakeServer{entries: map[int]miniflux.FeedEntry{}, reject: map[int]bool{}}
+	for _, entry := range entries {
+		f.entries[entry.ID] = entry
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/categories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, f.categories)
+	})
+	mux.HandleFunc("GET /v1/feeds", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, f.feeds)
+	})
+	mux.HandleFunc("GET /v1/entries", f.listEntries)
+	mux.HandleFunc("GET /v1/entries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := f.entries[pathID(r)]
+		if !ok {
+			http.Error(w, `{"error_message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, entry)
+	})
+	mux.HandleFunc("PUT /v1/entries", func(w http.ResponseWriter, r *http.Request) {
+		var req miniflux.UpdateEntriesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.sent = append(f.sent, fmt.Sprintf("PUT /v1/entries %s %v", req.Status, req.EntryIDs))
+		for _, id := range req.EntryIDs {
+			if f.reject[id] {
+				http.Error(w, `{"error_message":"invalid entry"}`, http.StatusBadRequest)
+				return
+			}
+		}
+		for _, id := range req.EntryIDs {
+			entry := f.entries[id]
+			entry.Status = miniflux.ReadStatus(req.Status)
+			f.entries[id] = entry
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PUT /v1/entries/{id}/bookmark", func(w http.ResponseWriter, r *http.Request) {
+		f.sent = append(f.sent, r.Method+" "+r.URL.Path)
+		entry := f.entries[pathID(r)]
+		entry.Starred = !entry.Starred
+		f.entries[entry.ID] = entry
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/entries/{id}/save", func(w http.ResponseWriter, r *http.Request) {
+		f.sent = append(f.sent, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.down {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return f, miniflux.NewClient(srv.URL, "key", false, miniflux.WithRetryPolicy(miniflux.RetryPolicy{}))
+}
+
+// listEntries supports the filters Sync uses, ordered by ID.
+func (f *fakeServer) listEntries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f.queries = append(f.queries, r.URL.RawQuery)
+	unix := func(key string) time.Time {
+		n, _ := strconv.ParseInt(q.Get(key), 10, 64)
+		return time.Unix(n, 0)
+	}
+
+	var matched []miniflux.FeedEntry
+	for _, entry := range f.entries {
+		switch {
+		case q.Has("status") && string(entry.Status) != q.Get("status"):
+		case q.Has("starred") && strconv.FormatBool(entry.Starred) != q.Get("starred"):
+		case q.Has("changed_after") && !entry.ChangedAt.After(unix("changed_after")):
+		case q.Has("published_after") && !entry.PublishedAt.After(unix("published_after")):
+		default:
+			matched = append(matched, entry)
+		}
+	}
+	slices.SortFunc(matched, func(a, b miniflux.FeedEntry) int { return a.ID - b.ID })
+
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	page := matched[min(offset, len(matched)):]
+	if limit > 0 && limit < len(page) {
+		page = page[:limit]
+	}
+	writeJSON(w, miniflux.FeedEntriesResponse{Total: len(matched), Entries: page})
+}
+
+func (f *fakeServer) entry(id int) miniflux.FeedEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.entries[id]
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func pathID(r *http.Request) int {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	return id
+}
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "cache.db"), "https://rss.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+var (
+	news = miniflux.Category{ID: 1, Title: "News"}
+	tech = miniflux.Category{ID: 2, Title: "Tech"}
+)
+
+func testEntry(id, feedID int, status miniflux.ReadStatus, published time.Time) miniflux.FeedEntry {
+	category := news
+	if feedID%2 == 0 {
+		category = tech
+	}
+	return miniflux.FeedEntry{
+		ID:          id,
+		FeedID:      feedID,
+		Title:       fmt.Sprintf("Entry %d", id),
+		Content:     fmt.Sprintf("<p>Body of entry %d</p>", id),
+		Feed:        miniflux.Feed{ID: feedID, Category: category},
+		Status:      status,
+		PublishedAt: published,
+		ChangedAt:   published,
+	}
+}
+
+func TestOpenOtherServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	s, err := Open(path, "https://one.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PutEntries([]miniflux.FeedEntry{testEntry(1, 1, miniflux.ReadStatusUnread, time.Now())}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Outbox().Add(ActionRead, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	for _, tc := range []struct {
+		server string
+		want   int
+	}{
+		{"https://one.example", 1},
+		{"https://two.example", 0},
+	} {
+		s, err := Open(path, tc.server)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, total, err := s.Entries(miniflux.EntryQuery{})
+		pending, _ := s.Outbox().Pending()
+		s.Close()
+		if err != nil || total != tc.want || len(pending) != tc.want {
+			t.Errorf("%s: %d entries and %d actions (%v), want %d", tc.server, total, len(pending), err, tc.want)
+		}
+	}
+}
+
+func TestEntries(t *testing.T) {
+	s := openTestStore(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	entries := []miniflux.FeedEntry{
+		testEntry(1, 1, miniflux.ReadStatusUnread, day),
+		testEntry(2, 2, miniflux.ReadStatusRead, day.Add(time.Hour)),
+		testEntry(3, 1, miniflux.ReadStatusUnread, day.Add(2*time.Hour)),
+		testEntry(4, 2, miniflux.ReadStatusUnread, day.Add(3*time.Hour)),
+	}
+	entries[1].Starred = true
+	entries[2].Content = `<p>Gophers <a href="https://go.dev">rejoice</a></p>`
+	if err := s.PutEntries(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	starred := true
+	unread := []miniflux.ReadStatus{miniflux.ReadStatusUnread}
+	for _, tc := range []struct {
+		name  string
+		q     miniflux.EntryQuery
+		want  []int
+		total int
+	}{
+		{"all by date", miniflux.EntryQuery{Order: miniflux.OrderPublishedAt, Direction: miniflux.DirectionDesc}, []int{4, 3, 2, 1}, 4},
+		{"unread", miniflux.EntryQuery{Status: unread, Order: miniflux.OrderID}, []int{1, 3, 4}, 3},
+		{"starred", miniflux.EntryQuery{Starred: &starred}, []int{2}, 1},
+		{"feed", miniflux.EntryQuery{FeedID: 1, Order: miniflux.OrderID}, []int{1, 3}, 2},
+		{"category", miniflux.EntryQuery{CategoryID: tech.ID, Order: miniflux.OrderID}, []int{2, 4}, 2},
+		{"published after", miniflux.EntryQuery{PublishedAfter: day.Add(90 * time.Minute), Order: miniflux.OrderID}, []int{3, 4}, 2},
+		{"page", miniflux.EntryQuery{Order: miniflux.OrderID, Limit: 2, Offset: 1}, []int{2, 3}, 4},
+		{"offset past the end", miniflux.EntryQuery{Order: miniflux.OrderID, Offset: 10}, nil, 4},
+		{"search title", miniflux.EntryQuery{Search: "ENTRY 4"}, []int{4}, 1},
+		{"search content", miniflux.EntryQuery{Search: "gophers rejoice"}, []int{3}, 1},
+		{"search skips markup", miniflux.EntryQuery{Search: "href"}, nil, 0},
+	} {
+		page, total, err := s.Entries(tc.q)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		var ids []int
+		for _, entry := range page {
+			ids = append(ids, entry.ID)
+		}
+		if !slices.Equal(ids, tc.want) || total != tc.total {
+			t.Errorf("%s: got %v of %d, want %v of %d", tc.name, ids, total, tc.want, tc.total)
+		}
+	}
+
+	got, ok, err := s.Entry(3)
+	if err != nil || !ok || got.Content != entries[2].Content {
+		t.Errorf("Entry(3) = %q, %v, %v", got.Content, ok, err)
+	}
+	if n, err := s.UnreadCount(); err != nil || n != 3 {
+		t.Errorf("UnreadCount = %d, %v, want 3", n, err)
+	}
+}
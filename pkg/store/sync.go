@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+	bolt "go.etcd.io/bbolt"
+)
+
+// syncBatch is the page size used when downloading entries.
+const syncBatch = 250
+
+// SyncResult summarizes a completed sync.
+type SyncResult struct {
+	// Full is set when the store was empty and everything was fetched.
+	Full bool
+	// Entries is how many new or changed entries were downloaded.
+	Entries int
+}
+
+// Sync brings the store up to date with the server. Feeds and categories
+// are replaced; entries are fetched incrementally with changed_after from
+// the newest change seen so far. The first sync instead downloads every
+// unread and starred entry plus everything published within retain.
+// Read, unstarred entries older than retain are then pruned.
+func (s *Store) Sync(ctx context.Context, c *miniflux.Client, retain time.Duration) (SyncResult, error) {
+	var result SyncResult
+	started := time.Now()
+
+	categories, err := c.GetCategories(ctx)
+	if err != nil {
+		return result, err
+	}
+	feeds, err := c.GetFeeds(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	cursor, err := s.Cursor()
+	if err != nil {
+		return result, err
+	}
+	var queries []miniflux.EntryQuery
+	if cursor.IsZero() {
+		result.Full = true
+		starred := true
+		queries = []miniflux.EntryQuery{
+			{Status: []miniflux.ReadStatus{miniflux.ReadStatusUnread}},
+			{Starred: &starred},
+			{PublishedAfter: started.Add(-retain)},
+		}
+	} else {
+		// changed_after is exclusive and has one second resolution
+		queries = []miniflux.EntryQuery{{ChangedAfter: cursor.Add(-time.Second)}}
+	}
+
+	// Entries with queued actions keep their local state until the
+	// actions reach the server
+	queued, err := s.Outbox().Pending()
+	if err != nil {
+		return result, err
+	}
+	pending := make(map[int]bool, len(queued))
+	for _, a := range queued {
+		pending[a.EntryID] = true
+	}
+
+	newest := cursor
+	for _, q := range queries {
+		q.Order, q.Direction, q.Limit = miniflux.OrderID, miniflux.DirectionAsc, syncBatch
+		for {
+			entries, total, err := c.GetEntries(ctx, q)
+			if err != nil {
+				return result, err
+			}
+			if err := s.keepLocalState(entries, pending); err != nil {
+				return result, err
+			}
+			if err := s.PutEntries(entries); err != nil {
+				return result, err
+			}
+			for _, entry := range entries {
+				if entry.ChangedAt.After(newest) {
+					newest = entry.ChangedAt
+				}
+			}
+			result.Entries += len(entries)
+			q.Offset += len(entries)
+			if len(entries) == 0 || q.Offset >= total {
+				break
+			}
+		}
+	}
+	if newest.IsZero() {
+		newest = started
+	}
+
+	if err := s.PutCategories(categories); err != nil {
+		return result, err
+	}
+	if err := s.PutFeeds(feeds); err != nil {
+		return result, err
+	}
+	if err := s.prune(feeds, started.Add(-retain)); err != nil {
+		return result, err
+	}
+	return result, s.setCursor(newest)
+}
+
+// keepLocalState copies the stored read and starred state onto entries
+// that have actions pending.
+func (s *Store) keepLocalState(entries []miniflux.FeedEntry, pending map[int]bool) error {
+	if len(pending) == 0 {
+		return nil
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketEntries)
+		for i := range entries {
+			if !pending[entries[i].ID] {
+				continue
+			}
+			v := b.Get(itob(entries[i].ID))
+			if v == nil {
+				continue
+			}
+			var local miniflux.FeedEntry
+			if err := json.Unmarshal(v, &local); err != nil {
+				return err
+			}
+			entries[i].Status, entries[i].Starred = local.Status, local.Starred
+		}
+		return nil
+	})
+}
+
+// prune drops entries of feeds that no longer exist and read, unstarred
+// entries that have not been published or changed since cutoff.
+func (s *Store) prune(feeds []miniflux.Feed, cutoff time.Time) error {
+	known := make(map[int]bool, len(feeds))
+	for _, feed := range feeds {
+		known[feed.ID] = true
+	}
+	var stale [][]byte
+	err := s.forEachIndex(func(r indexRecord) error {
+		old := r.Status == miniflux.ReadStatusRead && !r.Starred &&
+			r.PublishedAt.Before(cutoff) && r.ChangedAt.Before(cutoff)
+		if old || !known[r.FeedID] {
+			stale = append(stale, itob(r.ID))
+		}
+		return nil
+	})
+	if err != nil || len(stale) == 0 {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, k := range stale {
+			if err := deleteEntry(tx, k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
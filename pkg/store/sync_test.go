@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+func storedIDs(t *testing.T, s *Store) []int {
+	t.Helper()
+	entries, _, err := s.Entries(miniflux.EntryQuery{Order: miniflux.OrderID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func TestSync(t *testing.T) {
+	const retain = 7 * 24 * time.Hour
+	now := time.Now().Truncate(time.Second)
+	old := now.Add(-30 * 24 * time.Hour)
+
+	starredOld := testEntry(3, 1, miniflux.ReadStatusRead, old)
+	starredOld.Starred = true
+	f, client := newFakeServer(t,
+		testEntry(1, 1, miniflux.ReadStatusUnread, old),
+		testEntry(2, 2, miniflux.ReadStatusRead, now.Add(-time.Hour)),
+		starredOld,
+		testEntry(4, 1, miniflux.ReadStatusRead, old),
+	)
+	f.categories = []miniflux.Category{news, tech}
+	f.feeds = []miniflux.Feed{{ID: 1, Category: news}, {ID: 2, Category: tech}}
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	// The first sync fetches unread, starred and recent entries
+	result, err := s.Sync(ctx, client, retain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Full || len(f.queries) != 3 {
+		t.Errorf("first sync: Full = %v after %d queries, want a full sync", result.Full, len(f.queries))
+	}
+	if got := storedIDs(t, s); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("first sync stored %v, want [1 2 3]", got)
+	}
+	if cursor, _ := s.Cursor(); !cursor.Equal(now.Add(-time.Hour)) {
+		t.Errorf("cursor = %v, want the newest change %v", cursor, now.Add(-time.Hour))
+	}
+
+	// Entry 1 is read locally but the server does not know yet
+	if err := s.SetStatus([]int{1}, miniflux.ReadStatusRead); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Outbox().Add(ActionRead, 1); err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	changed := f.entries[1]
+	changed.Title, changed.ChangedAt = "Renamed", now
+	f.entries[1] = changed
+	changed = f.entries[2]
+	changed.Status, changed.ChangedAt = miniflux.ReadStatusUnread, now
+	f.entries[2] = changed
+	f.feeds = f.feeds[:1]
+	f.queries = nil
+	f.mu.Unlock()
+	// An old read entry, as if kept from an earlier sync
+	if err := s.PutEntries([]miniflux.FeedEntry{testEntry(5, 1, miniflux.ReadStatusRead, old)}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = s.Sync(ctx, client, retain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Full || len(f.queries) != 1 || !strings.Contains(f.queries[0], "changed_after=") {
+		t.Errorf("second sync: Full = %v with queries %q, want one changed_after query", result.Full, f.queries)
+	}
+	entry, _, err := s.Entry(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Title != "Renamed" || entry.Status != miniflux.ReadStatusRead {
+		t.Errorf("entry 1 = %q %s, want the new title with the local read status", entry.Title, entry.Status)
+	}
+	// Entry 2's feed was removed and entry 5 is read and old
+	if got := storedIDs(t, s); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("second sync stored %v, want [1 3]", got)
+	}
+	if n, err := s.UnreadCount(); err != nil || n != 0 {
+		t.Errorf("UnreadCount = %d, %v, want 0", n, err)
+	}
+}
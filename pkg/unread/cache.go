@@ -0,0 +1,61 @@
+package unread
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type cacheFile struct {
+	Fetched time.Time `json:"fetched"`
+	Counts  Counts    `json:"counts"`
+}
+
+// Cached returns the counts saved at path if they are younger than
+// maxAge. A status bar polling every few seconds then only reaches the
+// server once per maxAge.
+func Cached(path string, maxAge time.Duration) (Counts, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Counts{}, false
+	}
+	var cached cacheFile
+	if json.Unmarshal(data, &cached) != nil {
+		return Counts{}, false
+	}
+	if age := time.Since(cached.Fetched); age < 0 || age >= maxAge {
+		return Counts{}, false
+	}
+	return cached.Counts, true
+}
+
+// SaveCache saves counts at path for Cached.
+func SaveCache(path string, counts Counts) error {
+	return writeCache(path, cacheFile{Fetched: time.Now(), Counts: counts})
+}
+
+// writeCache replaces the cache file in one step so concurrent readers
+// never see a partial write.
+func writeCache(path string, v cacheFile) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".count-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,136 @@
+// Package unread summarizes unread entry counts for status bars and
+// shell prompts.
+package unread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/slatkin/goflux/pkg/miniflux"
+)
+
+// Counts is the number of unread entries, in total and per category.
+type Counts struct {
+	Total      int             `json:"total"`
+	Categories []CategoryCount `json:"categories"`
+}
+
+type CategoryCount struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Unread int    `json:"unread"`
+}
+
+// Styles lists the output styles Format accepts.
+var Styles = []string{"plain", "json", "i3bar", "waybar", "tmux"}
+
+// Fetch adds up the server's feed counters by category. Categories are
+// sorted by title and include those without unread entries.
+func Fetch(ctx context.Context, c *miniflux.Client) (Counts, error) {
+	categories, err := c.GetCategories(ctx)
+	if err != nil {
+		return Counts{}, err
+	}
+	feeds, err := c.GetFeeds(ctx)
+	if err != nil {
+		return Counts{}, err
+	}
+	counters, err := c.GetFeedCounters(ctx)
+	if err != nil {
+		return Counts{}, err
+	}
+
+	byCategory := make(map[int]int)
+	for _, feed := range feeds {
+		byCategory[feed.Category.ID] += counters.Unreads[feed.ID]
+	}
+	counts := Counts{Categories: make([]CategoryCount, len(categories))}
+	for i, category := range categories {
+		counts.Categories[i] = CategoryCount{ID: category.ID, Title: category.Title, Unread: byCategory[category.ID]}
+		counts.Total += byCategory[category.ID]
+	}
+	sort.Slice(counts.Categories, func(i, j int) bool {
+		return strings.ToLower(counts.Categories[i].Title) < strings.ToLower(counts.Categories[j].Title)
+	})
+	return counts, nil
+}
+
+// Format renders counts in one of Styles:
+//
+//   - plain: the total, then "<count>\t<category>" for each category with
+//     unread entries
+//   - json: Counts as JSON
+//   - i3bar: a block for the i3bar protocol, e.g. from i3blocks
+//   - waybar: a custom module result, with categories in the tooltip and
+//     class "unread" or "empty"
+//   - tmux: one line like "12 (News 5, Tech 7)", empty when nothing is
+//     unread so the status line collapses
+func (c Counts) Format(style string) (string, error) {
+	unread := c.unreadCategories()
+	switch style {
+	case "plain":
+		var s strings.Builder
+		s.WriteString(strconv.Itoa(c.Total) + "\n")
+		for _, cat := range unread {
+			fmt.Fprintf(&s, "%d\t%s\n", cat.Unread, cat.Title)
+		}
+		return s.String(), nil
+	case "json":
+		return marshal(c)
+	case "i3bar":
+		return marshal(map[string]string{
+			"name":       "goflux",
+			"full_text":  fmt.Sprintf("%d unread", c.Total),
+			"short_text": strconv.Itoa(c.Total),
+		})
+	case "waybar":
+		class := "empty"
+		if c.Total > 0 {
+			class = "unread"
+		}
+		lines := make([]string, len(unread))
+		for i, cat := range unread {
+			lines[i] = fmt.Sprintf("%s: %d", cat.Title, cat.Unread)
+		}
+		return marshal(map[string]string{
+			"text":    strconv.Itoa(c.Total),
+			"alt":     class,
+			"class":   class,
+			"tooltip": strings.Join(lines, "\n"),
+		})
+	case "tmux":
+		if c.Total == 0 {
+			return "\n", nil
+		}
+		parts := make([]string, len(unread))
+		for i, cat := range unread {
+			parts[i] = fmt.Sprintf("%s %d", cat.Title, cat.Unread)
+		}
+		// tmux expands #[...] and #(...) in status strings
+		line := fmt.Sprintf("%d (%s)", c.Total, strings.ReplaceAll(strings.Join(parts, ", "), "#", "##"))
+		return line + "\n", nil
+	}
+	return "", fmt.Errorf("unknown style %q (want %s)", style, strings.Join(Styles, ", "))
+}
+
+func (c Counts) unreadCategories() []CategoryCount {
+	var unread []CategoryCount
+	for _, cat := range c.Categories {
+		if cat.Unread > 0 {
+			unread = append(unread, cat)
+		}
+	}
+	return unread
+}
+
+func marshal(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
@@ -0,0 +1,91 @@
+package unread
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	counts := Counts{Total: 12, Categories: []CategoryCount{
+		{ID: 1, Title: "Empty", Unread: 0},
+		{ID: 2, Title: "News", Unread: 5},
+		{ID: 3, Title: "C#", Unread: 7},
+	}}
+	for _, tc := range []struct {
+		style  string
+		counts Counts
+		want   string
+	}{
+		{"plain", counts, "12\n5\tNews\n7\tC#\n"},
+		{"plain", Counts{}, "0\n"},
+		{"i3bar", counts, `{"full_text":"12 unread","name":"goflux","short_text":"12"}` + "\n"},
+		{"waybar", counts, `{"alt":"unread","class":"unread","text":"12","tooltip":"News: 5\nC#: 7"}` + "\n"},
+		{"waybar", Counts{}, `{"alt":"empty","class":"empty","text":"0","tooltip":""}` + "\n"},
+		{"tmux", counts, "12 (News 5, C## 7)\n"},
+		{"tmux", Counts{}, "\n"},
+	} {
+		got, err := tc.counts.Format(tc.style)
+		if err != nil {
+			t.Errorf("%s: %v", tc.style, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.style, got, tc.want)
+		}
+	}
+
+	got, err := counts.Format("json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Counts
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil || decoded.Total != 12 || len(decoded.Categories) != 3 {
+		t.Errorf("json: got %q (%v)", got, err)
+	}
+
+	if _, err := counts.Format("xml"); err == nil {
+		t.Error("expected an unknown style to fail")
+	}
+}
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counts", "default-count.json")
+	if _, ok := Cached(path, time.Minute); ok {
+		t.Fatal("Cached found a file that does not exist")
+	}
+
+	counts := Counts{Total: 3, Categories: []CategoryCount{{ID: 1, Title: "News", Unread: 3}}}
+	if err := SaveCache(path, counts); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := Cached(path, time.Minute); !ok || got.Total != 3 || len(got.Categories) != 1 {
+		t.Errorf("fresh cache: got %+v, %v", got, ok)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		fetched time.Time
+		ok      bool
+	}{
+		{"fresh", time.Now().Add(-30 * time.Second), true},
+		{"expired", time.Now().Add(-2 * time.Minute), false},
+		{"from the future", time.Now().Add(time.Hour), false},
+	} {
+		if err := writeCache(path, cacheFile{Fetched: tc.fetched, Counts: counts}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := Cached(path, time.Minute); ok != tc.ok {
+			t.Errorf("%s: Cached = %v, want %v", tc.name, ok, tc.ok)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Cached(path, time.Minute); ok {
+		t.Error("Cached accepted a corrupt file")
+	}
+}